@@ -1,8 +1,8 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
+	"strings"
 
 	"github.com/cidverse/normalizeci/pkg/normalizer"
 	"github.com/rs/zerolog/log"
@@ -15,9 +15,11 @@ func denormalizeCmd() *cobra.Command {
 		Short: "denormalizes information about the current CI environment",
 		Run: func(cmd *cobra.Command, args []string) {
 			format, _ := cmd.Flags().GetString("format")
-			outputFile, _ := cmd.Flags().GetString("output")
+			outputFiles, _ := cmd.Flags().GetStringArray("output")
+			formatOptions, _ := cmd.Flags().GetStringArray("format-opt")
 			strict, _ := cmd.Flags().GetBool("strict")
 			targets, _ := cmd.Flags().GetStringArray("target")
+			githubEventDir, _ := cmd.Flags().GetString("github-event-dir")
 
 			// run normalization
 			var normalized, err = normalizer.Normalize()
@@ -29,52 +31,31 @@ func denormalizeCmd() *cobra.Command {
 			outputEnv := make(map[string]string)
 
 			// targets
-			if len(targets) > 0 {
-				for _, target := range targets {
-					denormalized, err := normalizer.Denormalize(target, normalized)
-					if err != nil {
-						log.Fatal().Err(err).Str("target", target).Msg("denormalization failed")
-					}
-
-					for key, value := range denormalized {
-						outputEnv[key] = value
-					}
-				}
-			}
+			applyTargets(outputEnv, normalized, targets, githubEventDir)
 
 			// set process env
 			normalizer.SetProcessEnvironment(outputEnv)
 
-			// content?
-			content, err := normalizer.FormatEnvironment(outputEnv, format)
-			if err != nil {
-				log.Fatal().Str("format", format).Str("supported", "export,powershell,cmd").Msg("unsupported format!")
-			}
-
 			// validate?
 			if strict {
 				errors := normalized.Validate()
 				if len(errors) > 0 {
-					for _, line := range errors {
-						fmt.Printf("%s: %s [%s]\n", line.Field, line.Description, line.Value)
-					}
+					consoleOutput(formatValidationErrors(errors))
 					os.Exit(1)
 				}
 			}
 
 			// output
-			if len(outputFile) > 0 {
-				fileOutput(outputFile, content)
-			} else {
-				consoleOutput(content)
-			}
+			writeOutputs(normalizer.FormatInput{Env: outputEnv, Options: parseFormatOptions(formatOptions)}, parseOutputs(outputFiles, format))
 		},
 	}
 
-	cmd.PersistentFlags().StringP("format", "f", normalizer.GetDefaultFormat(), "The format in which to store the normalized variables. (export, powershell, cmd)")
-	cmd.PersistentFlags().StringP("output", "o", "", "Write output to this file instead of writing it to stdout.")
+	cmd.PersistentFlags().StringP("format", "f", normalizer.GetDefaultFormat(), "The format in which to store the normalized variables. ("+strings.Join(normalizer.GetFormats(), ", ")+")")
+	cmd.PersistentFlags().StringArrayP("output", "o", []string{}, "Write output to this file instead of writing it to stdout, use format=path to write a specific format. Can be repeated.")
+	cmd.PersistentFlags().StringArray("format-opt", []string{}, "Set a format option in the format key=value, e.g. key-style=dotted, key-prefix=ci or max-variables=20 for gitlab-dotenv. Can be repeated.")
 	cmd.PersistentFlags().Bool("strict", false, "Validate the generated variables against the spec and fail on errors?")
 	cmd.PersistentFlags().StringArrayP("target", "t", []string{}, "Additionally generates the environment for the target ci services")
+	cmd.PersistentFlags().String("github-event-dir", "", "Writes a synthetic GITHUB_EVENT_PATH payload into this directory when targeting github-actions")
 
 	return cmd
 }
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func detectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "detect",
+		Short: "shows which ci services match the current environment and why",
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+
+			detections := normalizer.DetectAll(api.GetMachineEnvironment())
+
+			switch format {
+			case "json":
+				jsonOutput(detections)
+			case "text":
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintln(w, "NAME\tSLUG\tMATCH\tDETAILS")
+				for _, detection := range detections {
+					match := "no"
+					if detection.Selected {
+						match = "selected"
+					} else if detection.Matched {
+						match = "yes"
+					}
+
+					var variables []string
+					for key, value := range detection.Variables {
+						variables = append(variables, key+"="+value)
+					}
+					sort.Strings(variables)
+					if detection.Details != "" {
+						variables = append(variables, detection.Details)
+					}
+
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", detection.Name, detection.Slug, match, strings.Join(variables, " "))
+				}
+				_ = w.Flush()
+			default:
+				log.Fatal().Str("format", format).Msg("unsupported format, supported: text, json")
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringP("format", "f", "text", "The output format. (text, json)")
+
+	return cmd
+}
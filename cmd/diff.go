@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// exit codes of the diff command, following diff(1)
+const (
+	diffExitDifferent = 1
+	diffExitTrouble   = 2
+)
+
+func diffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff a.json b.json",
+		Short: "compares two normalized snapshots field by field, exits with code 1 if they differ and 2 on errors",
+		Long:  "compares two normalized snapshots (json, export or dotenv format) field by field, exits with code 1 if they differ and 2 if a snapshot can't be loaded. Volatile fields (" + strings.Join(v1.VolatileFields, ", ") + ") are ignored by default.",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+			includeVolatile, _ := cmd.Flags().GetBool("include-volatile")
+			ignore, _ := cmd.Flags().GetStringArray("ignore")
+
+			snapshots := make([]v1.Spec, len(args))
+			for i, file := range args {
+				spec, err := loadSpecFile(file)
+				if err != nil {
+					log.Error().Err(err).Str("file", file).Msg("failed to load snapshot")
+					os.Exit(diffExitTrouble)
+				}
+				snapshots[i] = spec
+			}
+			a, b := snapshots[0], snapshots[1]
+
+			if !includeVolatile {
+				ignore = append(ignore, v1.VolatileFields...)
+			}
+			differences := v1.Diff(a, b, ignore)
+
+			switch format {
+			case "json":
+				if differences == nil {
+					differences = []v1.Difference{}
+				}
+				jsonOutput(differences)
+			case "text":
+				var sb strings.Builder
+				for _, d := range differences {
+					sb.WriteString(fmt.Sprintf("%s (%s): %q -> %q\n", d.Field, d.Env, d.A, d.B))
+				}
+				consoleOutput(sb.String())
+			default:
+				log.Error().Str("format", format).Msg("unsupported format, supported: text, json")
+				os.Exit(diffExitTrouble)
+			}
+
+			if len(differences) > 0 {
+				os.Exit(diffExitDifferent)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringP("format", "f", "text", "The output format. (text, json)")
+	cmd.PersistentFlags().Bool("include-volatile", false, "Compare fields that change on every run (e.g. job start time, ids)")
+	cmd.PersistentFlags().StringArray("ignore", []string{}, "Ignore a field by path (commit.hash) or env name (NCI_COMMIT_HASH). Can be repeated.")
+
+	return cmd
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	"github.com/cidverse/normalizeci/pkg/nciexec"
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func execCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec [flags] -- command [args...]",
+		Short: "runs a command with the normalized variables merged over the host environment",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			targets, _ := cmd.Flags().GetStringArray("target")
+			githubEventDir, _ := cmd.Flags().GetString("github-event-dir")
+
+			// run normalization
+			var normalized, err = normalizer.Normalize()
+			if err != nil {
+				log.Fatal().Err(err).Msg("normalization failed")
+			}
+
+			// transform to map
+			outputEnv := envstruct.StructToEnvMap(normalized)
+
+			// targets
+			applyTargets(outputEnv, normalized, targets, githubEventDir)
+
+			code, err := nciexec.Run(args[0], args[1:], nciexec.MergeEnv(os.Environ(), outputEnv))
+			if err != nil {
+				log.Error().Err(err).Str("command", args[0]).Msg("failed to execute command")
+			}
+			os.Exit(code)
+		},
+	}
+
+	// all arguments after the command belong to the command, even without --
+	cmd.Flags().SetInterspersed(false)
+	cmd.PersistentFlags().StringArrayP("target", "t", []string{}, "Additionally generates the environment for the target ci services")
+	cmd.PersistentFlags().String("github-event-dir", "", "Writes a synthetic GITHUB_EVENT_PATH payload into this directory when targeting github-actions")
+
+	return cmd
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// fieldExplanation is a field of the spec together with the source of its value
+type fieldExplanation struct {
+	Field  string     `json:"field"`
+	Env    string     `json:"env"`
+	Value  string     `json:"value"`
+	Source *v1.Source `json:"source"` // Source is nil if no source was recorded, e.g. for empty fields
+}
+
+func explainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain [field]",
+		Short: "prints where the value of each field came from (env, git, api, event, default or override)",
+		Long:  "prints where the value of each field came from (env, git, api, event, default or override) and the source key, e.g. the env variable. The field can be a path (project.name), a section (project) or an env name (NCI_PROJECT_NAME).",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+
+			// run normalization
+			normalized, prov, err := normalizer.NormalizeEnvWithProvenance(api.GetMachineEnvironment())
+			if err != nil {
+				log.Fatal().Err(err).Msg("normalization failed")
+			}
+
+			var explanations []fieldExplanation
+			for _, field := range normalized.Fields() {
+				if len(args) > 0 && !matchesField(field, args[0]) {
+					continue
+				}
+				explanation := fieldExplanation{Field: field.Path, Env: field.Env, Value: field.Value}
+				if source, ok := prov.Get(field.Path); ok {
+					explanation.Source = &source
+				}
+				explanations = append(explanations, explanation)
+			}
+			if len(args) > 0 && len(explanations) == 0 {
+				log.Fatal().Str("field", args[0]).Msg("unknown field")
+			}
+
+			switch format {
+			case "json":
+				jsonOutput(explanations)
+			case "text":
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintln(w, "FIELD\tENV\tVALUE\tSOURCE\tKEY")
+				for _, e := range explanations {
+					kind, key := "-", "-"
+					if e.Source != nil {
+						kind, key = e.Source.Kind, orDash(e.Source.Key)
+					}
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Field, e.Env, explainValue(e.Value), kind, key)
+				}
+				_ = w.Flush()
+			default:
+				log.Fatal().Str("format", format).Msg("unsupported format, supported: text, json")
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringP("format", "f", "text", "The output format. (text, json)")
+
+	return cmd
+}
+
+// matchesField returns true if the key is the path, the env name or the section of the field
+func matchesField(field v1.Field, key string) bool {
+	return strings.EqualFold(field.Path, key) || strings.EqualFold(field.Env, key) || strings.HasPrefix(strings.ToLower(field.Path), strings.ToLower(key)+".")
+}
+
+// explainValue keeps the table on one line per field
+func explainValue(value string) string {
+	value, _, multiline := strings.Cut(value, "\n")
+	if multiline {
+		value += " ..."
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func getCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get key [key...]",
+		Short: "prints the values of fields by path (commit.hashShort) or env name (NCI_COMMIT_HASH_SHORT)",
+		Long:  "prints the values of fields by path (commit.hashShort) or env name (NCI_COMMIT_HASH_SHORT), one value per line. Exits with code 1 if any value is empty.",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			defaultValue, _ := cmd.Flags().GetString("default")
+
+			// run normalization
+			var normalized, err = normalizer.Normalize()
+			if err != nil {
+				log.Fatal().Err(err).Msg("normalization failed")
+			}
+
+			var output strings.Builder
+			empty := false
+			for _, key := range args {
+				value, err := envstruct.Lookup(normalized, key)
+				if err != nil {
+					log.Fatal().Err(err).Str("key", key).Msg("invalid key")
+				}
+				if value == "" {
+					value = defaultValue
+				}
+				if value == "" {
+					empty = true
+				}
+				output.WriteString(value + "\n")
+			}
+
+			consoleOutput(output.String())
+			if empty {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().String("default", "", "The value used for empty fields")
+
+	return cmd
+}
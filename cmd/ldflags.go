@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/ldflags"
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func ldflagsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ldflags",
+		Short: "generates go linker flags to inject build metadata",
+		Run: func(cmd *cobra.Command, args []string) {
+			preset, _ := cmd.Flags().GetString("preset")
+			varValues, _ := cmd.Flags().GetStringArray("var")
+
+			var vars []ldflags.Var
+			if preset != "" {
+				presetVars, err := ldflags.GetPreset(preset)
+				if err != nil {
+					log.Fatal().Err(err).Msg("invalid preset")
+				}
+				vars = append(vars, presetVars...)
+			}
+			for _, value := range varValues {
+				v, err := ldflags.ParseVar(value)
+				if err != nil {
+					log.Fatal().Err(err).Msg("invalid variable")
+				}
+				vars = append(vars, v)
+			}
+			if len(vars) == 0 {
+				log.Fatal().Msg("no variables, use --preset or --var")
+			}
+
+			// run normalization
+			var normalized, err = normalizer.Normalize()
+			if err != nil {
+				log.Fatal().Err(err).Msg("normalization failed")
+			}
+
+			flags, err := ldflags.Build(normalized, vars)
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to generate ldflags")
+			}
+
+			fmt.Println(flags)
+		},
+	}
+
+	cmd.PersistentFlags().String("preset", "", "Use a preset variable layout. ("+strings.Join(ldflags.GetPresets(), ", ")+")")
+	cmd.PersistentFlags().StringArray("var", []string{}, "Set a variable to a spec field, e.g. main.version=commit.refRelease. Can be repeated.")
+
+	return cmd
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// normalizerInfo describes a registered normalizer
+type normalizerInfo struct {
+	Name                string `json:"name"`
+	Slug                string `json:"slug"`
+	Version             string `json:"version"`
+	SupportsDenormalize bool   `json:"supportsDenormalize"`
+}
+
+func listCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "lists all supported ci services",
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+
+			var infos []normalizerInfo
+			for _, n := range normalizer.GetNormalizers() {
+				infos = append(infos, normalizerInfo{Name: n.GetName(), Slug: n.GetSlug(), Version: n.GetVersion(), SupportsDenormalize: n.SupportsDenormalize()})
+			}
+
+			switch format {
+			case "json":
+				jsonOutput(infos)
+			case "text":
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintln(w, "NAME\tSLUG\tVERSION\tDENORMALIZE")
+				for _, info := range infos {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", info.Name, info.Slug, info.Version, info.SupportsDenormalize)
+				}
+				_ = w.Flush()
+			default:
+				log.Fatal().Str("format", format).Msg("unsupported format, supported: text, json")
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringP("format", "f", "text", "The output format. (text, json)")
+
+	return cmd
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
+	"strings"
 
 	"github.com/cidverse/normalizeci/pkg/envstruct"
 	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/cidverse/normalizeci/pkg/normalizer/gitlabci"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -16,9 +18,16 @@ func normalizeCmd() *cobra.Command {
 		Short: "normalizes information about the current CI environment",
 		Run: func(cmd *cobra.Command, args []string) {
 			format, _ := cmd.Flags().GetString("format")
-			outputFile, _ := cmd.Flags().GetString("output")
+			outputFiles, _ := cmd.Flags().GetStringArray("output")
+			formatOptions, _ := cmd.Flags().GetStringArray("format-opt")
 			strict, _ := cmd.Flags().GetBool("strict")
 			targets, _ := cmd.Flags().GetStringArray("target")
+			githubEventDir, _ := cmd.Flags().GetString("github-event-dir")
+			propagate, _ := cmd.Flags().GetBool("propagate")
+			propagateFile, _ := cmd.Flags().GetString("propagate-file")
+			propagateMaxVariables, _ := cmd.Flags().GetInt("propagate-max-variables")
+			propagateMaxSize, _ := cmd.Flags().GetInt("propagate-max-size")
+			secrets, _ := cmd.Flags().GetStringArray("secret")
 
 			// run normalization
 			var normalized, err = normalizer.Normalize()
@@ -26,57 +35,58 @@ func normalizeCmd() *cobra.Command {
 				log.Fatal().Err(err).Msg("normalization failed")
 			}
 
+			// validate?
+			if strict {
+				errors := normalized.Validate()
+				if len(errors) > 0 {
+					consoleOutput(formatValidationErrors(errors))
+					os.Exit(1)
+				}
+			}
+
 			// transform to map
 			outputEnv := envstruct.StructToEnvMap(normalized)
 
 			// targets
-			if len(targets) > 0 {
-				for _, target := range targets {
-					denormalized, err := normalizer.Denormalize(target, normalized)
-					if err != nil {
-						log.Fatal().Err(err).Str("target", target).Msg("denormalization failed")
-					}
-
-					for key, value := range denormalized {
-						outputEnv[key] = value
-					}
-				}
-			}
+			applyTargets(outputEnv, normalized, targets, githubEventDir)
 
 			// set process env
 			normalizer.SetProcessEnvironment(outputEnv)
 
-			// format content
-			content, err := normalizer.FormatEnvironment(outputEnv, format)
-			if err != nil {
-				log.Fatal().Str("format", format).Str("supported", "export,powershell,cmd").Msg("unsupported format!")
-			}
-
-			// validate?
-			if strict {
-				errors := normalized.Validate()
-				if len(errors) > 0 {
-					for _, line := range errors {
-						fmt.Printf("%s: %s [%s]\n", line.Field, line.Description, line.Value)
-					}
-					os.Exit(1)
+			// propagate to subsequent steps, using the native mechanism of the ci service
+			if propagate {
+				content, err := normalizer.Propagate(api.GetMachineEnvironment(), outputEnv, api.PropagateOptions{Secrets: secrets, File: propagateFile, DotenvMaxVariables: propagateMaxVariables, DotenvMaxSize: propagateMaxSize})
+				if err != nil {
+					log.Fatal().Err(err).Msg("failed to propagate variables")
 				}
-			}
 
-			// output
-			if len(outputFile) > 0 {
-				fileOutput(outputFile, content)
-			} else {
 				consoleOutput(content)
+				return
+			}
+
+			// output, structured formats contain the nested spec
+			outputs := parseOutputs(outputFiles, format)
+			for _, output := range outputs {
+				if normalizer.IsStructuredFormat(output.format) && len(targets) > 0 {
+					log.Warn().Str("format", output.format).Msg("target variables are not included in structured formats")
+				}
 			}
+			writeOutputs(normalizer.FormatInput{Spec: &normalized, Env: outputEnv, Options: parseFormatOptions(formatOptions)}, outputs)
 		},
 	}
 
-	cmd.PersistentFlags().StringP("format", "f", normalizer.GetDefaultFormat(), "The format in which to store the normalized variables. (export, powershell, cmd)")
-	cmd.PersistentFlags().StringP("output", "o", "", "Write output to this file instead of writing it to stdout.")
+	cmd.PersistentFlags().StringP("format", "f", normalizer.GetDefaultFormat(), "The format in which to store the normalized variables. ("+strings.Join(normalizer.GetFormats(), ", ")+")")
+	cmd.PersistentFlags().StringArrayP("output", "o", []string{}, "Write output to this file instead of writing it to stdout, use format=path to write a specific format. Can be repeated.")
+	cmd.PersistentFlags().StringArray("format-opt", []string{}, "Set a format option in the format key=value, e.g. key-style=dotted, key-prefix=ci or max-variables=20 for gitlab-dotenv. Can be repeated.")
 	cmd.PersistentFlags().Bool("strict", false, "Validate the generated variables against the spec and fail on errors?")
 	cmd.PersistentFlags().BoolP("version", "v", false, "all software has versions, this prints version information for normalizeci")
 	cmd.PersistentFlags().StringArrayP("target", "t", []string{}, "Additionally generates the environment for the target ci services")
+	cmd.PersistentFlags().String("github-event-dir", "", "Writes a synthetic GITHUB_EVENT_PATH payload into this directory when targeting github-actions")
+	cmd.PersistentFlags().Bool("propagate", false, "Makes the variables available to subsequent steps, using the native mechanism of the detected ci service")
+	cmd.PersistentFlags().String("propagate-file", "nci.env", "The dotenv report file written by --propagate on GitLab CI, must be declared as artifacts:reports:dotenv")
+	cmd.PersistentFlags().Int("propagate-max-variables", gitlabci.DotenvMaxVariables, "The maximum number of variables in the GitLab dotenv report, the default is the GitLab.com limit (self-managed instances default to 20)")
+	cmd.PersistentFlags().Int("propagate-max-size", gitlabci.DotenvMaxSize, "The maximum size of the GitLab dotenv report in bytes, the default is the GitLab.com limit")
+	cmd.PersistentFlags().StringArray("secret", []string{}, "Name of a variable that contains a secret and must be masked when propagated, can be repeated")
 
 	return cmd
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/cidverse/normalizeci/pkg/ocilabels"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func ociLabelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "oci-labels",
+		Short: "generates the OCI image labels / annotations for the current CI environment",
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+			annotations, _ := cmd.Flags().GetBool("annotations")
+			bakeTarget, _ := cmd.Flags().GetString("bake-target")
+			created, _ := cmd.Flags().GetString("created")
+			licenses, _ := cmd.Flags().GetString("licenses")
+			vendor, _ := cmd.Flags().GetString("vendor")
+
+			// run normalization
+			var normalized, err = normalizer.Normalize()
+			if err != nil {
+				log.Fatal().Err(err).Msg("normalization failed")
+			}
+
+			kind := "label"
+			if annotations {
+				kind = "annotation"
+			}
+
+			labels := ocilabels.FromSpec(normalized, ocilabels.Options{Created: created, Licenses: licenses, Vendor: vendor})
+			content, err := ocilabels.Format(labels, format, kind, bakeTarget)
+			if err != nil {
+				log.Fatal().Err(err).Str("format", format).Msg("failed to format labels")
+			}
+
+			fmt.Print(content)
+		},
+	}
+
+	cmd.PersistentFlags().StringP("format", "f", "docker", "The output format. (docker, bake, plain)")
+	cmd.PersistentFlags().Bool("annotations", false, "Generate annotations instead of labels")
+	cmd.PersistentFlags().String("bake-target", "default", "The target name used in the bake file")
+	cmd.PersistentFlags().String("created", "", "The creation date (RFC 3339), defaults to the job start time")
+	cmd.PersistentFlags().String("licenses", "", "The licenses of the image as SPDX license expression")
+	cmd.PersistentFlags().String("vendor", "", "The vendor of the image, defaults to the project namespace")
+
+	return cmd
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/cidverse/normalizeci/pkg/render"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func renderCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "renders a go template with the normalized spec as root object",
+		Run: func(cmd *cobra.Command, args []string) {
+			templateFile, _ := cmd.Flags().GetString("template")
+			outputFile, _ := cmd.Flags().GetString("output")
+
+			content, err := os.ReadFile(templateFile)
+			if err != nil {
+				log.Fatal().Err(err).Str("file", templateFile).Msg("failed to read template")
+			}
+
+			// run normalization
+			var normalized, normalizeErr = normalizer.Normalize()
+			if normalizeErr != nil {
+				log.Fatal().Err(normalizeErr).Msg("normalization failed")
+			}
+
+			output, err := render.Render(templateFile, string(content), normalized)
+			if err != nil {
+				log.Fatal().Err(err).Msg("render failed")
+			}
+
+			if len(outputFile) > 0 {
+				fileOutput(outputFile, output)
+			} else {
+				consoleOutput(output)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringP("template", "t", "", "The go template file to render, the spec is available as root object (e.g. {{ .Commit.Hash }})")
+	cmd.PersistentFlags().StringP("output", "o", "", "Write output to this file instead of writing it to stdout")
+	_ = cmd.MarkPersistentFlagRequired("template")
+
+	return cmd
+}
@@ -39,6 +39,20 @@ func RootCmd() *cobra.Command {
 	cmd.AddCommand(versionCmd())
 	cmd.AddCommand(normalizeCmd())
 	cmd.AddCommand(denormalizeCmd())
+	cmd.AddCommand(ociLabelsCmd())
+	cmd.AddCommand(ldflagsCmd())
+	cmd.AddCommand(renderCmd())
+	cmd.AddCommand(summaryCmd())
+	cmd.AddCommand(execCmd())
+	cmd.AddCommand(getCmd())
+	cmd.AddCommand(listCmd())
+	cmd.AddCommand(detectCmd())
+	cmd.AddCommand(validateCmd())
+	cmd.AddCommand(diffCmd())
+	cmd.AddCommand(explainCmd())
+	cmd.AddCommand(schemaCmd())
+	cmd.AddCommand(serveCmd())
+	cmd.AddCommand(simulateCmd())
 
 	return cmd
 }
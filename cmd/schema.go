@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func schemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "generates the json schema or a markdown reference of the normalized variables",
+		Long:  "generates the json schema (for the json format) or a markdown reference of the normalized variables, derived from the struct tags and doc comments of the spec.",
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+			outputFile, _ := cmd.Flags().GetString("output")
+
+			var output string
+			switch format {
+			case "jsonschema":
+				content, err := json.MarshalIndent(v1.GenerateJSONSchema(), "", "  ")
+				if err != nil {
+					log.Fatal().Err(err).Msg("failed to render json schema")
+				}
+				output = string(content) + "\n"
+			case "markdown":
+				output = v1.GenerateMarkdown()
+			default:
+				log.Fatal().Str("format", format).Msg("unsupported format, supported: jsonschema, markdown")
+			}
+
+			if len(outputFile) > 0 {
+				fileOutput(outputFile, output)
+			} else {
+				consoleOutput(output)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringP("format", "f", "jsonschema", "The output format. (jsonschema, markdown)")
+	cmd.PersistentFlags().StringP("output", "o", "", "Write output to this file instead of writing it to stdout")
+
+	return cmd
+}
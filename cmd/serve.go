@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	"github.com/cidverse/normalizeci/pkg/nciserve"
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func serveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "normalizes once and serves the spec over http, for containers that don't inherit the ci environment",
+		Long:  "normalizes once and serves the spec over http on a unix socket or tcp address, for containers that don't inherit the ci environment. Endpoints: /v1/spec, /v1/env (?format=export), /v1/fields/{key} and /healthz.",
+		Run: func(cmd *cobra.Command, args []string) {
+			socket, _ := cmd.Flags().GetString("socket")
+			listen, _ := cmd.Flags().GetString("listen")
+			token, _ := cmd.Flags().GetString("token")
+			if token == "" {
+				token = os.Getenv("NCI_SERVE_TOKEN")
+			}
+
+			network, address := "unix", socket
+			if listen != "" {
+				network, address = "tcp", listen
+			}
+			if socket == "" && listen == "" {
+				log.Fatal().Msg("either --socket or --listen is required")
+			}
+
+			// run normalization
+			var normalized, err = normalizer.Normalize()
+			if err != nil {
+				log.Fatal().Err(err).Msg("normalization failed")
+			}
+
+			handler := nciserve.NewHandler(normalized, envstruct.StructToEnvMap(normalized), nciserve.Options{Token: token})
+			if err := serve(network, address, handler, token != ""); err != nil {
+				log.Fatal().Err(err).Str("address", address).Msg("server failed")
+			}
+		},
+	}
+
+	cmd.PersistentFlags().String("socket", "", "Serve on this unix socket, e.g. /tmp/nci.sock")
+	cmd.PersistentFlags().String("listen", "", "Serve on this tcp address, e.g. 127.0.0.1:8080")
+	cmd.PersistentFlags().String("token", "", "Require this bearer token on all endpoints except /healthz, defaults to $NCI_SERVE_TOKEN")
+	cmd.MarkFlagsMutuallyExclusive("socket", "listen")
+
+	return cmd
+}
+
+// serve listens on the address until an interrupt is received, errors are returned to ensure the socket is removed
+func serve(network string, address string, handler http.Handler, auth bool) error {
+	listener, err := nciserve.Listen(network, address)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	if network == "unix" {
+		defer os.Remove(address)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Info().Str("network", network).Str("address", listener.Addr().String()).Bool("auth", auth).Msg("serving normalized spec")
+	return nciserve.Serve(ctx, listener, handler)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	"github.com/cidverse/normalizeci/pkg/nciexec"
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/cidverse/normalizeci/pkg/normalizer/githubactions"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func simulateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate --as target [flags] [-- command [args...]]",
+		Short: "simulates a ci environment based on the local repository, to test pipeline scripts locally",
+		Long:  "simulates a ci environment based on the local repository: the spec of the local repository is adjusted by the overrides (branch, tag, event, merge request) and denormalized into the native variables of the target. Runs the command within the environment, or prints the variables if no command is given.",
+		Run: func(cmd *cobra.Command, args []string) {
+			target, _ := cmd.Flags().GetString("as")
+			format, _ := cmd.Flags().GetString("format")
+			githubEventDir, _ := cmd.Flags().GetString("github-event-dir")
+			inputs, _ := cmd.Flags().GetStringArray("input")
+			opts := normalizer.SimulateOptions{Inputs: make(map[string]string)}
+			opts.Event, _ = cmd.Flags().GetString("event")
+			opts.Branch, _ = cmd.Flags().GetString("branch")
+			opts.Tag, _ = cmd.Flags().GetString("tag")
+			opts.MergeRequest, _ = cmd.Flags().GetString("merge-request")
+			opts.TargetBranch, _ = cmd.Flags().GetString("target-branch")
+			for _, input := range inputs {
+				key, value, found := strings.Cut(input, "=")
+				if !found || key == "" {
+					log.Fatal().Str("value", input).Msg("invalid input, expected format is key=value")
+				}
+				opts.Inputs[key] = value
+			}
+
+			spec, _, denormalized, err := normalizer.Simulate(api.GetMachineEnvironment(), target, opts)
+			if err != nil {
+				log.Fatal().Err(err).Str("target", target).Msg("simulation failed")
+			}
+
+			outputEnv := envstruct.StructToEnvMap(spec)
+			for key, value := range denormalized {
+				outputEnv[key] = value
+			}
+
+			// synthetic event payload, a temporary directory is used when running a command
+			tempDir := ""
+			if target == "github-actions" {
+				if githubEventDir == "" && len(args) > 0 {
+					tempDir, err = os.MkdirTemp("", "nci-simulate-")
+					if err != nil {
+						log.Fatal().Err(err).Msg("failed to create directory for the github event payload")
+					}
+					githubEventDir = tempDir
+				}
+				if githubEventDir != "" {
+					eventEnv, err := githubactions.WriteGithubEvent(spec, githubEventDir)
+					if err != nil {
+						log.Fatal().Err(err).Msg("failed to write github event payload")
+					}
+					for key, value := range eventEnv {
+						outputEnv[key] = value
+					}
+				}
+			}
+
+			if len(args) == 0 {
+				content, err := normalizer.FormatEnvironment(outputEnv, format)
+				if err != nil {
+					log.Fatal().Err(err).Str("format", format).Msg("failed to format variables")
+				}
+				consoleOutput(content)
+				return
+			}
+
+			code, err := nciexec.Run(args[0], args[1:], nciexec.MergeEnv(os.Environ(), outputEnv))
+			if err != nil {
+				log.Error().Err(err).Str("command", args[0]).Msg("failed to execute command")
+			}
+			if tempDir != "" {
+				_ = os.RemoveAll(tempDir)
+			}
+			os.Exit(code)
+		},
+	}
+
+	// all arguments after the command belong to the command, even without --
+	cmd.Flags().SetInterspersed(false)
+	cmd.PersistentFlags().String("as", "", "The ci service to simulate, e.g. github-actions or gitlab-ci")
+	cmd.PersistentFlags().String("event", "", "The event that triggered the pipeline, normalized (push, merge_request, schedule, manual) or native to the target (pull_request, merge_request_event, web)")
+	cmd.PersistentFlags().String("branch", "", "Simulate a pipeline for this branch instead of the current one")
+	cmd.PersistentFlags().String("tag", "", "Simulate a tag pipeline")
+	cmd.PersistentFlags().String("merge-request", "", "Simulate a merge request with this id, implies --event merge_request")
+	cmd.PersistentFlags().String("target-branch", "", "The target branch of the merge request, defaults to the default branch of the project")
+	cmd.PersistentFlags().StringArray("input", []string{}, "Set a pipeline input parameter in the format key=value. Can be repeated.")
+	cmd.PersistentFlags().String("github-event-dir", "", "Writes the synthetic GITHUB_EVENT_PATH payload into this directory, defaults to a temporary directory when running a command")
+	cmd.PersistentFlags().StringP("format", "f", normalizer.GetDefaultFormat(), "The format used to print the variables if no command is given. ("+strings.Join(normalizer.GetFormats(), ", ")+")")
+	_ = cmd.MarkPersistentFlagRequired("as")
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/cidverse/normalizeci/pkg/render"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func summaryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "summary",
+		Short: "writes a markdown summary of the build context to the job summary of the current CI environment",
+		Run: func(cmd *cobra.Command, args []string) {
+			templateFile, _ := cmd.Flags().GetString("template")
+			stdout, _ := cmd.Flags().GetBool("stdout")
+
+			var template string
+			if len(templateFile) > 0 {
+				content, err := os.ReadFile(templateFile)
+				if err != nil {
+					log.Fatal().Err(err).Str("file", templateFile).Msg("failed to read template")
+				}
+				template = string(content)
+			}
+
+			// run normalization
+			var normalized, err = normalizer.Normalize()
+			if err != nil {
+				log.Fatal().Err(err).Msg("normalization failed")
+			}
+
+			markdown, err := render.Summary(templateFile, template, normalized)
+			if err != nil {
+				log.Fatal().Err(err).Msg("render failed")
+			}
+
+			if stdout {
+				consoleOutput(markdown)
+				return
+			}
+
+			content, err := normalizer.WriteSummary(api.GetMachineEnvironment(), markdown)
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to write summary")
+			}
+			consoleOutput(content)
+		},
+	}
+
+	cmd.PersistentFlags().StringP("template", "t", "", "A go template file to use instead of the default summary, the spec is available as root object")
+	cmd.PersistentFlags().Bool("stdout", false, "Write the summary to stdout instead of the job summary of the ci service")
+
+	return cmd
+}
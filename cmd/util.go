@@ -1,9 +1,19 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strings"
 
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/cidverse/normalizeci/pkg/normalizer/githubactions"
 	"github.com/rs/zerolog/log"
 )
 
@@ -21,3 +31,142 @@ func consoleOutput(content string) {
 		log.Err(err).Msg("failed to write content to stdout")
 	}
 }
+
+// jsonOutput writes the data as indented json to stdout
+func jsonOutput(data interface{}) {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to render json")
+	}
+
+	consoleOutput(string(content) + "\n")
+}
+
+// outputTarget is a destination for the formatted output, an empty path writes to stdout
+type outputTarget struct {
+	format string
+	path   string
+}
+
+// formatNameRegex matches values that look like a format name, used to reject typos in format=path
+var formatNameRegex = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// parseOutputs parses the values of --output, each value is either a path (written in the default format) or format=path.
+// A path containing = must be prefixed with ./ if the part before = looks like a format name.
+func parseOutputs(values []string, defaultFormat string) []outputTarget {
+	if len(values) == 0 {
+		return []outputTarget{{format: defaultFormat}}
+	}
+
+	var outputs []outputTarget
+	for _, value := range values {
+		if format, path, found := strings.Cut(value, "="); found {
+			if _, ok := normalizer.GetFormatter(format); ok {
+				outputs = append(outputs, outputTarget{format: format, path: path})
+				continue
+			}
+			if formatNameRegex.MatchString(format) {
+				log.Fatal().Str("format", format).Str("supported", strings.Join(normalizer.GetFormats(), ",")).Msg("unsupported output format, use ./" + value + " to write to a file with this name")
+			}
+		}
+
+		outputs = append(outputs, outputTarget{format: defaultFormat, path: value})
+	}
+
+	return outputs
+}
+
+// parseFormatOptions parses the values of --format-opt in the format key=value
+func parseFormatOptions(values []string) map[string]string {
+	options := make(map[string]string)
+	for _, value := range values {
+		key, optionValue, found := strings.Cut(value, "=")
+		if !found || key == "" {
+			log.Fatal().Str("value", value).Msg("invalid format option, expected format is key=value")
+		}
+		options[key] = optionValue
+	}
+
+	return options
+}
+
+// writeOutputs renders the input for all outputs, nothing is written if any of the formats fails
+func writeOutputs(input normalizer.FormatInput, outputs []outputTarget) {
+	contents := make([]string, len(outputs))
+	for i, output := range outputs {
+		content, err := normalizer.Format(input, output.format)
+		if err != nil {
+			log.Fatal().Err(err).Str("format", output.format).Str("supported", strings.Join(normalizer.GetFormats(), ",")).Msg("failed to format environment")
+		}
+		contents[i] = content
+	}
+
+	for i, output := range outputs {
+		if len(output.path) > 0 {
+			fileOutput(output.path, contents[i])
+		} else {
+			consoleOutput(contents[i])
+		}
+	}
+}
+
+// applyTargets adds the denormalized variables of the target ci services to the environment
+func applyTargets(env map[string]string, normalized v1.Spec, targets []string, githubEventDir string) {
+	for _, target := range targets {
+		denormalized, err := normalizer.Denormalize(target, normalized)
+		if err != nil {
+			log.Fatal().Err(err).Str("target", target).Msg("denormalization failed")
+		}
+
+		for key, value := range denormalized {
+			env[key] = value
+		}
+
+		// synthetic event payload
+		if target == "github-actions" && len(githubEventDir) > 0 {
+			eventEnv, err := githubactions.WriteGithubEvent(normalized, githubEventDir)
+			if err != nil {
+				log.Fatal().Err(err).Str("target", target).Msg("failed to write github event payload")
+			}
+
+			for key, value := range eventEnv {
+				env[key] = value
+			}
+		}
+	}
+}
+
+// formatValidationErrors renders one line per error, e.g. NCI_WORKER_ARCH: must be os/arch (e.g. linux/amd64), got "amd64"
+func formatValidationErrors(errors []common.ValidationError) string {
+	var sb strings.Builder
+	for _, e := range errors {
+		if e.IsMissing() {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", e.Env, e.Message))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s: %s, got %q\n", e.Env, e.Message, e.Value))
+		}
+	}
+
+	return sb.String()
+}
+
+// loadSpecFile loads a spec from a json document or a file in the export or dotenv format
+func loadSpecFile(file string) (v1.Spec, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return v1.Spec{}, err
+	}
+
+	if strings.HasSuffix(file, ".json") {
+		return normalizer.ParseSpecJSON(content)
+	}
+
+	env, err := nciutil.ParseEnvFile(string(content))
+	if err != nil {
+		return v1.Spec{}, err
+	}
+
+	var spec v1.Spec
+	err = envstruct.EnvMapToStruct(&spec, env)
+	return spec, err
+}
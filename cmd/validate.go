@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// exit codes of the validate command
+const (
+	validateExitMissing   = 2
+	validateExitMalformed = 3
+)
+
+// validationResult is the json output of the validate command
+type validationResult struct {
+	Valid  bool                     `json:"valid"`
+	Errors []common.ValidationError `json:"errors"`
+}
+
+func validateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "validates normalized variables from the environment or a file against the spec",
+		Long:  "validates normalized variables from the environment or a file against the spec. Exits with code 2 if required values are missing and code 3 if values are malformed.",
+		Run: func(cmd *cobra.Command, args []string) {
+			envFile, _ := cmd.Flags().GetString("env-file")
+			format, _ := cmd.Flags().GetString("format")
+
+			var spec v1.Spec
+			if len(envFile) > 0 {
+				var err error
+				spec, err = loadSpecFile(envFile)
+				if err != nil {
+					log.Fatal().Err(err).Str("file", envFile).Msg("failed to load variables")
+				}
+			} else if err := envstruct.EnvMapToStruct(&spec, api.GetMachineEnvironment()); err != nil {
+				log.Fatal().Err(err).Msg("failed to load variables")
+			}
+
+			errors := spec.Validate()
+			switch format {
+			case "json":
+				jsonOutput(validationResult{Valid: len(errors) == 0, Errors: errors})
+			case "text":
+				consoleOutput(formatValidationErrors(errors))
+			default:
+				log.Fatal().Str("format", format).Msg("unsupported format, supported: text, json")
+			}
+
+			// missing values take precedence over malformed values
+			exitCode := 0
+			for _, e := range errors {
+				if e.IsMissing() {
+					exitCode = validateExitMissing
+					break
+				}
+				exitCode = validateExitMalformed
+			}
+			os.Exit(exitCode)
+		},
+	}
+
+	cmd.PersistentFlags().String("env-file", "", "Validate the variables of this file (export, dotenv or json format) instead of the environment")
+	cmd.PersistentFlags().StringP("format", "f", "text", "The output format. (text, json)")
+
+	return cmd
+}
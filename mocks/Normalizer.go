@@ -70,6 +70,20 @@ func (_m *Normalizer) GetSlug() string {
 	return r0
 }
 
+// GetVersion provides a mock function with given fields:
+func (_m *Normalizer) GetVersion() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // Normalize provides a mock function with given fields: env
 func (_m *Normalizer) Normalize(env map[string]string) v1.Spec {
 	ret := _m.Called(env)
@@ -84,6 +98,20 @@ func (_m *Normalizer) Normalize(env map[string]string) v1.Spec {
 	return r0
 }
 
+// SupportsDenormalize provides a mock function with given fields:
+func (_m *Normalizer) SupportsDenormalize() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewNormalizer interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,59 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Lookup returns the value of a field, the key is either the env name (NCI_COMMIT_HASH) or the path of the field using the json names (commit.hashShort)
+func Lookup(data interface{}, key string) (string, error) {
+	if value, ok := StructToEnvMap(data)[key]; ok {
+		return value, nil
+	}
+
+	value := reflect.ValueOf(data)
+	path := strings.Split(key, ".")
+	for i, segment := range path {
+		switch value.Kind() {
+		case reflect.Map:
+			// map entries (e.g. inputs) are optional, a missing entry is empty
+			if i != len(path)-1 {
+				return "", fmt.Errorf("field %s not found", key)
+			}
+			entry := value.MapIndex(reflect.ValueOf(segment))
+			if !entry.IsValid() {
+				return "", nil
+			}
+			return entry.String(), nil
+		case reflect.Struct:
+			field, ok := fieldByName(value, segment)
+			if !ok {
+				return "", fmt.Errorf("field %s not found", key)
+			}
+			value = field
+		default:
+			return "", fmt.Errorf("field %s not found", key)
+		}
+	}
+
+	if value.Kind() != reflect.String {
+		return "", fmt.Errorf("field %s is a section, not a value", key)
+	}
+
+	return value.String(), nil
+}
+
+// fieldByName returns the struct field with the json name, or the field name (case-insensitive)
+func fieldByName(value reflect.Value, name string) (reflect.Value, bool) {
+	reflectType := value.Type()
+	for i := 0; i < reflectType.NumField(); i++ {
+		fieldType := reflectType.Field(i)
+		jsonName, _, _ := strings.Cut(fieldType.Tag.Get("json"), ",")
+		if jsonName == name || strings.EqualFold(fieldType.Name, name) {
+			return value.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
@@ -0,0 +1,49 @@
+package envstruct
+
+import (
+	"testing"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	spec := v1.Create("GitHub Actions", "github-actions")
+	spec.Commit.RefRelease = "1.2.3"
+	spec.Commit.RefVCS = "refs/tags/v1.2.3"
+	spec.Commit.HashShort = "f1e2d3c4"
+	spec.Pipeline.Input = map[string]string{"name": "value"}
+
+	for key, expected := range map[string]string{
+		"commit.refRelease":     "1.2.3",
+		"commit.refVcs":         "refs/tags/v1.2.3",
+		"Commit.RefVCS":         "refs/tags/v1.2.3",
+		"serviceSlug":           "github-actions",
+		"pipeline.inputs.name":  "value",
+		"pipeline.inputs.other": "",
+		"NCI_COMMIT_HASH_SHORT": "f1e2d3c4",
+		"NCI_SERVICE_SLUG":      "github-actions",
+		"NCI_INPUT_NAME":        "value",
+		"NCI_COMMIT_TITLE":      "",
+	} {
+		value, err := Lookup(spec, key)
+		assert.NoError(t, err, key)
+		assert.Equal(t, expected, value, key)
+	}
+}
+
+func TestLookup_Invalid(t *testing.T) {
+	spec := v1.Create("GitHub Actions", "github-actions")
+
+	_, err := Lookup(spec, "commit.unknown")
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = Lookup(spec, "commit")
+	assert.ErrorContains(t, err, "section")
+
+	_, err = Lookup(spec, "commit.hash.extra")
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = Lookup(spec, "NCI_UNKNOWN")
+	assert.ErrorContains(t, err, "not found")
+}
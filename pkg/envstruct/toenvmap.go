@@ -2,9 +2,13 @@ package envstruct
 
 import (
 	"reflect"
+	"regexp"
 	"strings"
 )
 
+// invalidEnvNameChars matches characters that can't be used in environment variable names (ie. the `-` of the input `log-level`)
+var invalidEnvNameChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
 func StructToEnvMap(data interface{}) map[string]string {
 	return structToEnvMap(data, "")
 }
@@ -24,7 +28,7 @@ func structToEnvMap(data interface{}, prefix string) map[string]string {
 			subMap := field.Interface().(map[string]string)
 
 			for k, v := range subMap {
-				result[envPrefixTag+strings.ToUpper(k)] = v
+				result[envPrefixTag+invalidEnvNameChars.ReplaceAllString(strings.ToUpper(k), "_")] = v
 			}
 		} else if fieldType.Type.Kind() == reflect.Struct {
 			subMap := structToEnvMap(field.Interface(), "")
@@ -73,6 +73,25 @@ func TestStructToEnvMapWithSubMap(t *testing.T) {
 	}
 }
 
+func TestStructToEnvMapWithInvalidMapKeys(t *testing.T) {
+	myStruct := withMap{
+		PipelineId: "123",
+		PipelineInput: map[string]string{
+			"log-level":   "debug",
+			"deploy.zone": "eu",
+		},
+	}
+
+	result := StructToEnvMap(myStruct)
+
+	if result["NCI_INPUT_LOG_LEVEL"] != "debug" {
+		t.Errorf("Unexpected value for key %q. Expected: %v, Got: %v", "NCI_INPUT_LOG_LEVEL", "debug", result["NCI_INPUT_LOG_LEVEL"])
+	}
+	if result["NCI_INPUT_DEPLOY_ZONE"] != "eu" {
+		t.Errorf("Unexpected value for key %q. Expected: %v, Got: %v", "NCI_INPUT_DEPLOY_ZONE", "eu", result["NCI_INPUT_DEPLOY_ZONE"])
+	}
+}
+
 type withSubStruct struct {
 	Hello    string `env:"NCI_HELLO"`
 	Pipeline withMap
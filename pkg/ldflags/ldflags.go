@@ -0,0 +1,114 @@
+package ldflags
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+)
+
+// Var links a go package variable to a spec field
+type Var struct {
+	Name  string // Name is the fully qualified variable name, e.g. main.version
+	Field string // Field is the json path or env name of the spec field, e.g. commit.refRelease
+}
+
+// presets for common variable layouts
+var presets = map[string][]Var{
+	// goreleaser default ldflags
+	"goreleaser": {
+		{Name: "main.version", Field: "commit.refRelease"},
+		{Name: "main.commit", Field: "commit.hash"},
+		{Name: "main.date", Field: "pipeline.jobStartedAt"},
+	},
+	// layout of the normalizeci app.go
+	"normalizeci": {
+		{Name: "main.version", Field: "commit.refRelease"},
+		{Name: "main.commit", Field: "commit.hash"},
+		{Name: "main.date", Field: "pipeline.jobStartedAt"},
+		{Name: "main.status", Field: "repository.status"},
+	},
+}
+
+// GetPreset returns the variables of a preset
+func GetPreset(name string) ([]Var, error) {
+	vars, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %s, supported presets: %s", name, strings.Join(GetPresets(), ", "))
+	}
+
+	return vars, nil
+}
+
+// GetPresets returns the names of all presets, sorted by name
+func GetPresets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseVar parses a variable in the format name=field, e.g. main.version=commit.refRelease
+func ParseVar(value string) (Var, error) {
+	name, field, found := strings.Cut(value, "=")
+	if !found || name == "" || field == "" {
+		return Var{}, fmt.Errorf("invalid variable %q, expected format is name=field", value)
+	}
+	if strings.ContainsAny(name, " \t\r\n'\"") {
+		return Var{}, fmt.Errorf("invalid variable name %q", name)
+	}
+
+	return Var{Name: name, Field: field}, nil
+}
+
+// Build renders the -X flags for the variables, the result can be passed to go build -ldflags as is.
+// Later variables overwrite earlier variables with the same name.
+func Build(spec v1.Spec, vars []Var) (string, error) {
+	var order []string
+	values := make(map[string]string)
+	for _, v := range vars {
+		value, err := envstruct.Lookup(spec, v.Field)
+		if err != nil {
+			return "", err
+		}
+		if _, exists := values[v.Name]; !exists {
+			order = append(order, v.Name)
+		}
+		values[v.Name] = value
+	}
+
+	args := make([]string, 0, len(order)*2)
+	for _, name := range order {
+		arg, err := quote(name + "=" + values[name])
+		if err != nil {
+			return "", fmt.Errorf("can't pass the value of %s: %w", name, err)
+		}
+		args = append(args, "-X", arg)
+	}
+
+	return strings.Join(args, " "), nil
+}
+
+// quote quotes an argument for the -ldflags parser of the go command, which splits on spaces and supports single and double quotes without escapes
+func quote(arg string) (string, error) {
+	if strings.ContainsAny(arg, "\r\n") {
+		return "", errors.New("line breaks are not supported")
+	}
+	if arg != "" && !strings.ContainsAny(arg, " \t'\"") {
+		return arg, nil
+	}
+	if !strings.Contains(arg, "'") {
+		return "'" + arg + "'", nil
+	}
+	if !strings.Contains(arg, `"`) {
+		return `"` + arg + `"`, nil
+	}
+
+	return "", errors.New("values containing both single and double quotes are not supported")
+}
@@ -0,0 +1,85 @@
+package ldflags
+
+import (
+	"testing"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSpec() v1.Spec {
+	spec := v1.Create("GitHub Actions", "github-actions")
+	spec.Commit.RefRelease = "1.2.3"
+	spec.Commit.Hash = "f1e2d3c4b5a6"
+	spec.Commit.Title = "it's a \"test\""
+	spec.Commit.AuthorName = "Jane Doe"
+	spec.Pipeline.JobStartedAt = "2024-01-02T03:04:05Z"
+	spec.Repository.Status = "clean"
+	return spec
+}
+
+func TestBuildPreset(t *testing.T) {
+	vars, err := GetPreset("goreleaser")
+	assert.NoError(t, err)
+
+	flags, err := Build(testSpec(), vars)
+	assert.NoError(t, err)
+	assert.Equal(t, "-X main.version=1.2.3 -X main.commit=f1e2d3c4b5a6 -X main.date=2024-01-02T03:04:05Z", flags)
+}
+
+func TestBuildQuoting(t *testing.T) {
+	flags, err := Build(testSpec(), []Var{
+		{Name: "main.author", Field: "commit.authorName"},
+		{Name: "main.empty", Field: "commit.description"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "-X 'main.author=Jane Doe' -X main.empty=", flags)
+
+	spec := testSpec()
+	spec.Commit.Title = "it's a test"
+	flags, err = Build(spec, []Var{{Name: "main.title", Field: "commit.title"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `-X "main.title=it's a test"`, flags)
+}
+
+func TestBuildUnsupportedValue(t *testing.T) {
+	_, err := Build(testSpec(), []Var{{Name: "main.title", Field: "commit.title"}})
+	assert.Error(t, err)
+
+	spec := testSpec()
+	spec.Commit.Title = "title\nbody"
+	_, err = Build(spec, []Var{{Name: "main.title", Field: "commit.title"}})
+	assert.Error(t, err)
+}
+
+func TestBuildOverwrite(t *testing.T) {
+	vars, _ := GetPreset("goreleaser")
+	vars = append(vars, Var{Name: "main.version", Field: "commit.hash"})
+
+	flags, err := Build(testSpec(), vars)
+	assert.NoError(t, err)
+	assert.Equal(t, "-X main.version=f1e2d3c4b5a6 -X main.commit=f1e2d3c4b5a6 -X main.date=2024-01-02T03:04:05Z", flags)
+}
+
+func TestBuildUnknownField(t *testing.T) {
+	_, err := Build(testSpec(), []Var{{Name: "main.version", Field: "commit.version"}})
+	assert.Error(t, err)
+}
+
+func TestParseVar(t *testing.T) {
+	v, err := ParseVar("main.version=commit.refRelease")
+	assert.NoError(t, err)
+	assert.Equal(t, Var{Name: "main.version", Field: "commit.refRelease"}, v)
+
+	for _, value := range []string{"main.version", "=commit.hash", "main.version=", "main version=commit.hash"} {
+		_, err = ParseVar(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestGetPreset(t *testing.T) {
+	assert.Equal(t, []string{"goreleaser", "normalizeci"}, GetPresets())
+
+	_, err := GetPreset("unknown")
+	assert.ErrorContains(t, err, "goreleaser, normalizeci")
+}
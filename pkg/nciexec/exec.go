@@ -0,0 +1,109 @@
+package nciexec
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// exit codes if the command can't be started, following the shell convention
+const (
+	ExitCodeNotExecutable = 126 // ExitCodeNotExecutable is returned if the command was found but can't be executed
+	ExitCodeNotFound      = 127 // ExitCodeNotFound is returned if the command doesn't exist
+)
+
+// Run executes the command with the environment, connected to the standard streams of the current process.
+// Signals received by the current process are forwarded to the command, the returned exit code is the exit code of the command.
+func Run(name string, args []string, env []string) (int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// register before starting the command, so no signal is missed
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return ExitCodeNotFound, fmt.Errorf("failed to start %s: %w", name, err)
+		}
+		return ExitCodeNotExecutable, fmt.Errorf("failed to start %s: %w", name, err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				forwardSignal(cmd.Process, sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitCode(exitErr.ProcessState), nil
+		}
+		return 1, fmt.Errorf("failed to wait for %s: %w", name, err)
+	}
+
+	return 0, nil
+}
+
+// MergeEnv returns the environment (in os.Environ format) with the variables set, existing variables are replaced.
+// Variable names are case-insensitive on windows.
+func MergeEnv(environ []string, vars map[string]string) []string {
+	return mergeEnv(environ, vars, runtime.GOOS == "windows")
+}
+
+func mergeEnv(environ []string, vars map[string]string, caseInsensitive bool) []string {
+	normalizeKey := func(key string) string {
+		if caseInsensitive {
+			return strings.ToUpper(key)
+		}
+		return key
+	}
+
+	overrides := make(map[string]string, len(vars))
+	for key := range vars {
+		overrides[normalizeKey(key)] = key
+	}
+
+	result := make([]string, 0, len(environ)+len(vars))
+	for _, entry := range environ {
+		// windows has hidden variables like =C:=C:\dir, the name never starts with the separator
+		key, _, found := strings.Cut(entry, "=")
+		if found && key == "" {
+			result = append(result, entry)
+			continue
+		}
+		if _, ok := overrides[normalizeKey(key)]; ok {
+			continue
+		}
+		result = append(result, entry)
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		result = append(result, key+"="+vars[key])
+	}
+
+	return result
+}
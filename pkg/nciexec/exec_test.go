@@ -0,0 +1,58 @@
+package nciexec
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMain allows the test binary to act as the executed command
+func TestMain(m *testing.M) {
+	switch os.Getenv("NCI_EXEC_TEST_HELPER") {
+	case "exit":
+		code, _ := strconv.Atoi(os.Getenv("NCI_EXEC_TEST_CODE"))
+		os.Exit(code)
+	case "signal":
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM)
+		fmt.Println("ready")
+		select {
+		case <-signals:
+			os.Exit(42)
+		case <-time.After(10 * time.Second):
+			os.Exit(1)
+		}
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestRun_ExitCode(t *testing.T) {
+	code, err := Run(os.Args[0], nil, MergeEnv(os.Environ(), map[string]string{"NCI_EXEC_TEST_HELPER": "exit", "NCI_EXEC_TEST_CODE": "3"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, code)
+
+	code, err = Run(os.Args[0], nil, MergeEnv(os.Environ(), map[string]string{"NCI_EXEC_TEST_HELPER": "exit", "NCI_EXEC_TEST_CODE": "0"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, code)
+}
+
+func TestRun_NotFound(t *testing.T) {
+	code, err := Run("nci-command-that-does-not-exist", nil, os.Environ())
+	assert.Error(t, err)
+	assert.Equal(t, ExitCodeNotFound, code)
+}
+
+func TestMergeEnv(t *testing.T) {
+	environ := []string{"PATH=/bin", "NCI_COMMIT_HASH=old", "=C:=C:\\dir", "Home=/home"}
+	vars := map[string]string{"NCI_COMMIT_HASH": "new", "NCI": "true", "HOME": "/root"}
+
+	assert.Equal(t, []string{"PATH=/bin", "=C:=C:\\dir", "Home=/home", "HOME=/root", "NCI=true", "NCI_COMMIT_HASH=new"}, mergeEnv(environ, vars, false))
+	assert.Equal(t, []string{"PATH=/bin", "=C:=C:\\dir", "HOME=/root", "NCI=true", "NCI_COMMIT_HASH=new"}, mergeEnv(environ, vars, true))
+}
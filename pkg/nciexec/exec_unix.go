@@ -0,0 +1,24 @@
+//go:build !windows
+
+package nciexec
+
+import (
+	"os"
+	"syscall"
+)
+
+// forwardedSignals are passed on to the command
+var forwardedSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT, syscall.SIGUSR1, syscall.SIGUSR2}
+
+func forwardSignal(process *os.Process, sig os.Signal) {
+	_ = process.Signal(sig)
+}
+
+// exitCode returns the exit code of the process, a process terminated by a signal exits with 128 + signal number (shell convention)
+func exitCode(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+
+	return state.ExitCode()
+}
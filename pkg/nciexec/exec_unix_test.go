@@ -0,0 +1,48 @@
+//go:build !windows
+
+package nciexec
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_ForwardSignal(t *testing.T) {
+	// capture the output of the child, the signal is sent to the current process once the child is ready and must be forwarded
+	reader, writer, err := os.Pipe()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer reader.Close()
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	go func() {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			if scanner.Text() == "ready" {
+				_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+			}
+		}
+	}()
+
+	code, err := Run(os.Args[0], nil, MergeEnv(os.Environ(), map[string]string{"NCI_EXEC_TEST_HELPER": "signal"}))
+	_ = writer.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, code)
+}
+
+func TestRun_NotExecutable(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "script.sh")
+	assert.NoError(t, os.WriteFile(file, []byte("#!/bin/sh\n"), 0o644))
+
+	code, err := Run(file, nil, os.Environ())
+	assert.Error(t, err)
+	assert.Equal(t, ExitCodeNotExecutable, code)
+}
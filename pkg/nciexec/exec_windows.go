@@ -0,0 +1,19 @@
+//go:build windows
+
+package nciexec
+
+import (
+	"os"
+)
+
+// forwardedSignals are caught to keep the current process alive until the command exits
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// forwardSignal does nothing, console control events (CTRL+C) are delivered to all processes attached to the console, including the command
+func forwardSignal(process *os.Process, sig os.Signal) {
+}
+
+// exitCode returns the exit code of the process
+func exitCode(state *os.ProcessState) int {
+	return state.ExitCode()
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package nciserve
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnix creates the socket with a restrictive umask, so it is never accessible by other users
+func listenUnix(address string) (net.Listener, error) {
+	umask := syscall.Umask(0o177)
+	defer syscall.Umask(umask)
+
+	return net.Listen("unix", address)
+}
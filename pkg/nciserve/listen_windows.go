@@ -0,0 +1,12 @@
+//go:build windows
+
+package nciserve
+
+import (
+	"net"
+)
+
+// listenUnix creates the socket, access is controlled by the acl of the directory as windows ignores the file mode
+func listenUnix(address string) (net.Listener, error) {
+	return net.Listen("unix", address)
+}
@@ -0,0 +1,150 @@
+package nciserve
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/normalizer"
+	"github.com/rs/zerolog/log"
+)
+
+// ShutdownTimeout is the time given to running requests on shutdown
+const ShutdownTimeout = 5 * time.Second
+
+// Options configures the handler
+type Options struct {
+	Token string // Token is required as bearer token for all endpoints except /healthz, empty disables auth
+}
+
+// NewHandler returns the http handler that serves the normalized spec
+//
+// Endpoints:
+//   - GET /healthz
+//   - GET /v1/spec - the spec as json
+//   - GET /v1/env - the variables as json object, ?format=<format> renders any output format (e.g. export, dotenv)
+//   - GET /v1/fields/{key} - a single value by path (commit.hash) or env name (NCI_COMMIT_HASH)
+func NewHandler(spec v1.Spec, env map[string]string, opts Options) http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /v1/spec", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, spec)
+	})
+	api.HandleFunc("GET /v1/env", func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			writeJSON(w, env)
+			return
+		}
+
+		content, err := normalizer.Format(normalizer.FormatInput{Spec: &spec, Env: env}, format)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(content))
+	})
+	api.HandleFunc("GET /v1/fields/{key}", func(w http.ResponseWriter, r *http.Request) {
+		value, err := envstruct.Lookup(spec, r.PathValue("key"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(value))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.Handle("/", requireToken(opts.Token, api))
+
+	return mux
+}
+
+// requireToken rejects requests without the bearer token
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="normalizeci"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		log.Err(err).Msg("failed to write response")
+	}
+}
+
+// Listen opens a unix socket (network unix) or tcp listener, a stale socket file is removed (other files are left untouched) and the socket is only accessible by the current user
+func Listen(network string, address string) (net.Listener, error) {
+	if network != "unix" {
+		return net.Listen(network, address)
+	}
+
+	info, err := os.Lstat(address)
+	if err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s already exists and is not a socket", address)
+		}
+		if err = os.Remove(address); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", address, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to check socket %s: %w", address, err)
+	}
+
+	return listenUnix(address)
+}
+
+// Serve serves the handler until the context is cancelled, running requests are given ShutdownTimeout to complete
+func Serve(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package nciserve
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func testHandler(token string) http.Handler {
+	spec := v1.Create("GitLab CI", "gitlab-ci")
+	spec.Commit.Hash = "5e48b2b4"
+	return NewHandler(spec, map[string]string{"NCI_COMMIT_HASH": "5e48b2b4"}, Options{Token: token})
+}
+
+func request(handler http.Handler, path string, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	handler := testHandler("")
+
+	rec := request(handler, "/v1/spec", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"hash": "5e48b2b4"`)
+
+	rec = request(handler, "/v1/env", "")
+	assert.JSONEq(t, `{"NCI_COMMIT_HASH": "5e48b2b4"}`, rec.Body.String())
+
+	rec = request(handler, "/v1/env?format=export", "")
+	assert.Equal(t, "export NCI_COMMIT_HASH='5e48b2b4'\n", rec.Body.String())
+
+	rec = request(handler, "/v1/env?format=unknown", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = request(handler, "/v1/fields/NCI_COMMIT_HASH", "")
+	assert.Equal(t, "5e48b2b4", rec.Body.String())
+	rec = request(handler, "/v1/fields/commit.hash", "")
+	assert.Equal(t, "5e48b2b4", rec.Body.String())
+	rec = request(handler, "/v1/fields/commit.unknown", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandler_Token(t *testing.T) {
+	handler := testHandler("secret")
+
+	assert.Equal(t, http.StatusUnauthorized, request(handler, "/v1/spec", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, request(handler, "/v1/spec", "wrong").Code)
+	assert.Equal(t, http.StatusOK, request(handler, "/v1/spec", "secret").Code)
+	assert.Equal(t, http.StatusOK, request(handler, "/healthz", "").Code)
+}
+
+func TestServe_Socket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "nci.sock")
+	listener, err := Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets are not supported: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, listener, testHandler(""))
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://nci/v1/fields/commit.hash")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "5e48b2b4", string(body))
+
+	// graceful shutdown
+	cancel()
+	assert.NoError(t, <-done)
+}
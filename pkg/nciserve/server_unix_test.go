@@ -0,0 +1,53 @@
+//go:build !windows
+
+package nciserve
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListen_SocketPermissions(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "nci.sock")
+	listener, err := Listen("unix", socket)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	info, err := os.Stat(socket)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestListen_StaleSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "nci.sock")
+	stale, err := Listen("unix", socket)
+	if !assert.NoError(t, err) {
+		return
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	assert.NoError(t, stale.Close())
+
+	listener, err := Listen("unix", socket)
+	if !assert.NoError(t, err) {
+		return
+	}
+	listener.Close()
+}
+
+func TestListen_RegularFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "nci.sock")
+	assert.NoError(t, os.WriteFile(file, []byte("content"), 0o644))
+
+	_, err := Listen("unix", file)
+	assert.ErrorContains(t, err, "is not a socket")
+
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+}
@@ -1,6 +1,9 @@
 package common
 
-import "regexp"
+import (
+	"regexp"
+	"strings"
+)
 
 const (
 	alphaRegexString        = "^[a-zA-Z]+$"
@@ -8,11 +11,13 @@ const (
 	numberRegexString       = "^\\d+$"
 	emailRegexString        = "^(?:(?:(?:(?:[a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+(?:\\.([a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+)*)|(?:(?:\\x22)(?:(?:(?:(?:\\x20|\\x09)*(?:\\x0d\\x0a))?(?:\\x20|\\x09)+)?(?:(?:[\\x01-\\x08\\x0b\\x0c\\x0e-\\x1f\\x7f]|\\x21|[\\x23-\\x5b]|[\\x5d-\\x7e]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[\\x01-\\x09\\x0b\\x0c\\x0d-\\x7f]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}]))))*(?:(?:(?:\\x20|\\x09)*(?:\\x0d\\x0a))?(\\x20|\\x09)+)?(?:\\x22))))@(?:(?:(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])(?:[a-zA-Z]|\\d|-|\\.|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.)+(?:(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])(?:[a-zA-Z]|\\d|-|\\.|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.?$"
 	repositoryKindString    = "^(git|svn)$"
-	pipelineTriggerString   = "^(manual|push|trigger|api|schedule|pull_request|build)$"
 	slugString              = "^[a-z0-9]+(?:-[a-z0-9]+)*$"
 	archString              = "^(linux|windows|darwin)/[a-z0-9]+$"
 )
 
+// pipelineTriggerString is derived from the trigger constants, to keep it in sync with the spec
+var pipelineTriggerString = "^(" + strings.Join(PipelineTriggers, "|") + ")$"
+
 var (
 	alphaRegex           = regexp.MustCompile(alphaRegexString)
 	alphaNumericRegex    = regexp.MustCompile(alphaNumericRegexString)
@@ -23,3 +28,17 @@ var (
 	slugRegex            = regexp.MustCompile(slugString)
 	archRegex            = regexp.MustCompile(archString)
 )
+
+// ValidationPattern returns the regular expression of a validation rule, empty if the rule has no pattern
+func ValidationPattern(rule string) string {
+	switch rule {
+	case "number":
+		return numberRegexString
+	case "is-slug":
+		return slugString
+	case "is-arch":
+		return archString
+	}
+
+	return ""
+}
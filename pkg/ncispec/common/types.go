@@ -10,6 +10,17 @@ const (
 	PipelineTriggerUnknown      = "unknown"
 )
 
+// PipelineTriggers holds all values of the pipeline trigger
+var PipelineTriggers = []string{
+	PipelineTriggerCLI,
+	PipelineTriggerManual,
+	PipelineTriggerPush,
+	PipelineTriggerMergeRequest,
+	PipelineTriggerSchedule,
+	PipelineTriggerBuild,
+	PipelineTriggerUnknown,
+}
+
 const (
 	PipelineStageDefault = "default"
 	PipelineJobDefault   = "default"
@@ -1,6 +1,8 @@
 package common
 
 import (
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -14,9 +16,34 @@ func init() {
 }
 
 type ValidationError struct {
-	Field       string
-	Value       string
-	Description string
+	Field       string `json:"field"`       // Field is the struct field name
+	Env         string `json:"env"`         // Env is the environment variable name of the field
+	Value       string `json:"value"`       // Value is the invalid value
+	Description string `json:"description"` // Description is the validation rule that failed (e.g. required)
+	Message     string `json:"message"`     // Message is a human-readable description of the problem
+}
+
+// IsMissing returns true if a required value is missing, other errors are malformed values
+func (e ValidationError) IsMissing() bool {
+	return e.Description == "required"
+}
+
+// ValidationMessage returns a human-readable message for a failed validation rule
+func ValidationMessage(rule string, param string) string {
+	switch rule {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "number":
+		return "must be a number"
+	case "is-slug":
+		return "must be a slug (lowercase letters, digits and single dashes, e.g. my-project)"
+	case "is-arch":
+		return "must be os/arch (e.g. linux/amd64)"
+	}
+
+	return "failed the " + rule + " validation"
 }
 
 func validateSlug(fl validator.FieldLevel) bool {
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// VolatileFields change on every run and are ignored by Diff unless requested explicitly
+var VolatileFields = []string{
+	"worker.id",
+	"pipeline.id",
+	"pipeline.stageId",
+	"pipeline.jobId",
+	"pipeline.jobStartedAt",
+	"pipeline.url",
+}
+
+// Difference is a field with different values in two specs
+type Difference struct {
+	Field string `json:"field"` // Field is the json path of the field, e.g. commit.hash
+	Env   string `json:"env"`   // Env is the environment variable name of the field
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+// Field is a value of the spec with its json path and env name
+type Field struct {
+	Path  string
+	Env   string
+	Value string
+}
+
+// Fields returns all values of the spec, pipeline inputs are included as pipeline.inputs.<name>
+func (spec Spec) Fields() []Field {
+	return collectFields(reflect.ValueOf(spec), "")
+}
+
+func collectFields(value reflect.Value, prefix string) []Field {
+	var fields []Field
+	for i := 0; i < value.NumField(); i++ {
+		fieldType := value.Type().Field(i)
+		jsonName, _, _ := strings.Cut(fieldType.Tag.Get("json"), ",")
+		path := prefix + jsonName
+
+		switch fieldType.Type.Kind() {
+		case reflect.Struct:
+			fields = append(fields, collectFields(value.Field(i), path+".")...)
+		case reflect.Map:
+			entries := value.Field(i).Interface().(map[string]string)
+			keys := make([]string, 0, len(entries))
+			for key := range entries {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fields = append(fields, Field{Path: path + "." + key, Env: fieldType.Tag.Get("env-prefix") + strings.ToUpper(key), Value: entries[key]})
+			}
+		default:
+			fields = append(fields, Field{Path: path, Env: fieldType.Tag.Get("env"), Value: value.Field(i).String()})
+		}
+	}
+
+	return fields
+}
+
+// Diff compares two specs field by field, fields can be ignored by json path or env name
+func Diff(a Spec, b Spec, ignore []string) []Difference {
+	ignored := make(map[string]bool, len(ignore))
+	for _, field := range ignore {
+		ignored[field] = true
+	}
+
+	valuesA := make(map[string]Field)
+	var order []string
+	for _, field := range a.Fields() {
+		valuesA[field.Path] = field
+		order = append(order, field.Path)
+	}
+	valuesB := make(map[string]Field)
+	for _, field := range b.Fields() {
+		if _, ok := valuesA[field.Path]; !ok {
+			order = append(order, field.Path)
+		}
+		valuesB[field.Path] = field
+	}
+
+	var differences []Difference
+	for _, path := range order {
+		fieldA, fieldB := valuesA[path], valuesB[path]
+		env := fieldA.Env
+		if env == "" {
+			env = fieldB.Env
+		}
+		if ignored[path] || ignored[env] || fieldA.Value == fieldB.Value {
+			continue
+		}
+
+		differences = append(differences, Difference{Field: path, Env: env, A: fieldA.Value, B: fieldB.Value})
+	}
+
+	return differences
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := Create("GitLab CI", "gitlab-ci")
+	a.Commit.Hash = "abc"
+	a.Pipeline.JobStartedAt = "2024-01-01T00:00:00Z"
+	a.Pipeline.Input = map[string]string{"removed": "x", "same": "y"}
+
+	b := Create("GitLab CI", "gitlab-ci")
+	b.Commit.Hash = "def"
+	b.Pipeline.JobStartedAt = "2024-01-02T00:00:00Z"
+	b.Pipeline.Input = map[string]string{"added": "z", "same": "y"}
+
+	assert.Equal(t, []Difference{
+		{Field: "pipeline.inputs.removed", Env: "NCI_INPUT_REMOVED", A: "x", B: ""},
+		{Field: "commit.hash", Env: "NCI_COMMIT_HASH", A: "abc", B: "def"},
+		{Field: "pipeline.inputs.added", Env: "NCI_INPUT_ADDED", A: "", B: "z"},
+	}, Diff(a, b, VolatileFields))
+
+	assert.Len(t, Diff(a, b, nil), 4)
+	assert.Len(t, Diff(a, b, []string{"NCI_COMMIT_HASH", "pipeline.jobStartedAt", "pipeline.inputs.added", "NCI_INPUT_REMOVED"}), 0)
+	assert.Empty(t, Diff(a, a, nil))
+}
+
+func TestFields(t *testing.T) {
+	spec := Create("GitLab CI", "gitlab-ci")
+	fields := spec.Fields()
+
+	assert.Equal(t, Field{Path: "found", Env: "NCI", Value: "true"}, fields[0])
+	assert.Contains(t, fields, Field{Path: "commit.refVcs", Env: "NCI_COMMIT_REF_VCS", Value: ""})
+	assert.Contains(t, fields, Field{Path: "flags.deployFreeze", Env: "NCI_DEPLOY_FREEZE", Value: ""})
+}
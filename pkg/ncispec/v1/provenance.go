@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"strings"
+)
+
+// source kinds of a field
+const (
+	SourceEnv      = "env"      // SourceEnv is a variable of the ci environment
+	SourceGit      = "git"      // SourceGit is the local repository
+	SourceAPI      = "api"      // SourceAPI is the api of the repository host or ci service
+	SourceEvent    = "event"    // SourceEvent is the event payload of the ci service (e.g. GITHUB_EVENT_PATH)
+	SourceDefault  = "default"  // SourceDefault is a fixed value or a value computed by normalizeci (e.g. runtime arch, current time)
+	SourceOverride = "override" // SourceOverride is a value set explicitly by the caller
+)
+
+// Source describes where the value of a field came from
+type Source struct {
+	Kind string `json:"kind"`          // Kind is the type of the source, see the Source* constants
+	Key  string `json:"key,omitempty"` // Key identifies the value within the source, e.g. the env variable name or the event field
+}
+
+// Candidate is a possible value of a field together with its source, see Provenance.First
+type Candidate struct {
+	Value  string
+	Source Source
+}
+
+// Provenance maps the json path of each field (e.g. commit.hash) to the source of its value
+type Provenance map[string]Source
+
+// Set records the source of a field
+func (p Provenance) Set(field string, kind string, key string) {
+	p[field] = Source{Kind: kind, Key: key}
+}
+
+// Env records the env variable as source of the field and returns its value
+func (p Provenance) Env(field string, env map[string]string, key string) string {
+	p.Set(field, SourceEnv, key)
+	return env[key]
+}
+
+// Default records a fixed or computed value as source of the field and returns it
+func (p Provenance) Default(field string, value string) string {
+	p.Set(field, SourceDefault, "")
+	return value
+}
+
+// First returns the first non-empty candidate and records its source, the source of the last candidate is recorded if all are empty
+func (p Provenance) First(field string, candidates ...Candidate) string {
+	for _, candidate := range candidates {
+		if candidate.Value != "" {
+			p[field] = candidate.Source
+			return candidate.Value
+		}
+	}
+
+	if len(candidates) > 0 {
+		p[field] = candidates[len(candidates)-1].Source
+	}
+	return ""
+}
+
+// SetSection records the source for all non-empty fields of a section, used when a whole section is copied (e.g. commit from the repository)
+func (p Provenance) SetSection(spec Spec, section string, kind string, key string) {
+	for _, field := range spec.Fields() {
+		if strings.HasPrefix(field.Path, section+".") && field.Value != "" {
+			p.Set(field.Path, kind, key)
+		}
+	}
+}
+
+// Get returns the source of a field, ok is false if no source was recorded
+func (p Provenance) Get(field string) (Source, bool) {
+	source, ok := p[field]
+	return source, ok
+}
+
+// EnvCandidate creates a candidate from an env variable
+func EnvCandidate(env map[string]string, key string) Candidate {
+	return Candidate{Value: env[key], Source: Source{Kind: SourceEnv, Key: key}}
+}
+
+// SourceCandidate creates a candidate from a value of any source
+func SourceCandidate(value string, kind string, key string) Candidate {
+	return Candidate{Value: value, Source: Source{Kind: kind, Key: key}}
+}
+
+// Complete records all non-empty fields without a source as default, e.g. fixed values like the worker type
+func (p Provenance) Complete(spec Spec) {
+	for _, field := range spec.Fields() {
+		if _, ok := p[field.Path]; !ok && field.Value != "" {
+			p.Set(field.Path, SourceDefault, "")
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvenance_First(t *testing.T) {
+	prov := Provenance{}
+	env := map[string]string{"CI_PROJECT_TITLE": ""}
+
+	value := prov.First("project.name", EnvCandidate(env, "CI_PROJECT_TITLE"), SourceCandidate("normalizeci", SourceAPI, "gitlab"))
+	assert.Equal(t, "normalizeci", value)
+	assert.Equal(t, Source{Kind: SourceAPI, Key: "gitlab"}, prov["project.name"])
+
+	env["CI_PROJECT_TITLE"] = "NormalizeCI"
+	value = prov.First("project.name", EnvCandidate(env, "CI_PROJECT_TITLE"), SourceCandidate("normalizeci", SourceAPI, "gitlab"))
+	assert.Equal(t, "NormalizeCI", value)
+	assert.Equal(t, Source{Kind: SourceEnv, Key: "CI_PROJECT_TITLE"}, prov["project.name"])
+}
+
+func TestProvenance_First_Empty(t *testing.T) {
+	prov := Provenance{}
+
+	value := prov.First("project.url", EnvCandidate(map[string]string{}, "CI_PROJECT_URL"))
+	assert.Equal(t, "", value)
+	assert.Equal(t, Source{Kind: SourceEnv, Key: "CI_PROJECT_URL"}, prov["project.url"])
+}
+
+func TestProvenance_SetSection(t *testing.T) {
+	prov := Provenance{}
+	spec := Spec{Commit: Commit{Hash: "abc", RefName: "main"}}
+
+	prov.SetSection(spec, "commit", SourceGit, "HEAD")
+	assert.Equal(t, Provenance{
+		"commit.hash":    {Kind: SourceGit, Key: "HEAD"},
+		"commit.refName": {Kind: SourceGit, Key: "HEAD"},
+	}, prov)
+}
+
+func TestProvenance_Complete(t *testing.T) {
+	prov := Provenance{}
+	spec := Create("GitLab CI", "gitlab-ci")
+	spec.Worker.Id = "12270837"
+	prov.Env("worker.id", map[string]string{"CI_RUNNER_ID": "12270837"}, "CI_RUNNER_ID")
+
+	prov.Complete(spec)
+	assert.Equal(t, Source{Kind: SourceEnv, Key: "CI_RUNNER_ID"}, prov["worker.id"])
+	assert.Equal(t, Source{Kind: SourceDefault}, prov["serviceSlug"])
+	assert.NotContains(t, prov, "worker.name")
+}
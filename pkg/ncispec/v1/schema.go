@@ -0,0 +1,214 @@
+package v1
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+)
+
+// specSource is the source of the spec, the field descriptions are taken from the doc comments
+//
+//go:embed spec.go
+var specSource string
+
+// SchemaField describes a field of the spec, derived from the struct tags and doc comments
+type SchemaField struct {
+	Path        string   // Path is the json path of the field, e.g. commit.hash
+	Env         string   // Env is the environment variable name, for maps the prefix of the variable names
+	Description string   // Description is the doc comment of the field
+	Required    bool     // Required is true if the field must not be empty
+	Pattern     string   // Pattern is the regular expression the value must match
+	Enum        []string // Enum holds the allowed values
+	Rules       []string // Rules holds a human-readable message for each validation rule except required
+	Map         bool     // Map is true for fields with dynamic keys, e.g. pipeline.inputs
+}
+
+// JSONSchema is a subset of the JSON Schema (draft 2020-12) used to describe the spec
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Id                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	MinLength            int                    `json:"minLength,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Env                  string                 `json:"x-env,omitempty"`        // Env is the environment variable name of the field
+	EnvPrefix            string                 `json:"x-env-prefix,omitempty"` // EnvPrefix is the environment variable name prefix of map entries
+}
+
+// SchemaFields returns all fields of the spec in declaration order
+func SchemaFields() []SchemaField {
+	comments := fieldComments()
+	return collectSchemaFields(reflect.TypeOf(Spec{}), "", comments)
+}
+
+func collectSchemaFields(structType reflect.Type, prefix string, comments map[string]string) []SchemaField {
+	var fields []SchemaField
+	for i := 0; i < structType.NumField(); i++ {
+		fieldType := structType.Field(i)
+		jsonName, _, _ := strings.Cut(fieldType.Tag.Get("json"), ",")
+		path := prefix + jsonName
+
+		switch fieldType.Type.Kind() {
+		case reflect.Struct:
+			fields = append(fields, collectSchemaFields(fieldType.Type, path+".", comments)...)
+		case reflect.Map:
+			fields = append(fields, SchemaField{
+				Path:        path,
+				Env:         fieldType.Tag.Get("env-prefix"),
+				Description: comments[structType.Name()+"."+fieldType.Name],
+				Map:         true,
+			})
+		default:
+			field := SchemaField{
+				Path:        path,
+				Env:         fieldType.Tag.Get("env"),
+				Description: comments[structType.Name()+"."+fieldType.Name],
+			}
+			applyValidationRules(&field, fieldType.Tag.Get("validate"))
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// applyValidationRules translates the validate tag into the schema constraints
+func applyValidationRules(field *SchemaField, tag string) {
+	if tag == "" {
+		return
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			field.Required = true
+			continue
+		case "oneof":
+			field.Enum = strings.Fields(param)
+		default:
+			field.Pattern = common.ValidationPattern(name)
+		}
+		field.Rules = append(field.Rules, common.ValidationMessage(name, param))
+	}
+}
+
+// fieldComments parses the doc comments of the struct fields in the spec, keyed by Type.Field
+func fieldComments() map[string]string {
+	comments := make(map[string]string)
+	file, err := parser.ParseFile(token.NewFileSet(), "spec.go", specSource, parser.ParseComments)
+	if err != nil {
+		return comments
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		typeSpec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+
+		for _, field := range structType.Fields.List {
+			text := strings.TrimSpace(field.Doc.Text() + field.Comment.Text())
+			// some comments contain a second comment in the same line
+			text, _, _ = strings.Cut(text, "//")
+			for _, name := range field.Names {
+				comments[typeSpec.Name.Name+"."+name.Name] = strings.TrimSpace(text)
+			}
+		}
+		return false
+	})
+
+	return comments
+}
+
+// GenerateJSONSchema generates a JSON Schema for the json representation of the spec
+func GenerateJSONSchema() *JSONSchema {
+	root := &JSONSchema{
+		Schema:      "https://json-schema.org/draft/2020-12/schema",
+		Id:          "https://cidverse.github.io/normalizeci/spec/v1/schema.json",
+		Title:       "NormalizeCI Spec",
+		Description: "The normalized information about the ci environment, repository and commit.",
+		Type:        "object",
+		Properties:  make(map[string]*JSONSchema),
+	}
+
+	for _, field := range SchemaFields() {
+		parent := root
+		segments := strings.Split(field.Path, ".")
+		for _, segment := range segments[:len(segments)-1] {
+			section, ok := parent.Properties[segment]
+			if !ok {
+				section = &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+				parent.Properties[segment] = section
+				parent.Required = append(parent.Required, segment)
+			}
+			parent = section
+		}
+
+		name := segments[len(segments)-1]
+		property := &JSONSchema{Type: "string", Description: field.Description, Pattern: field.Pattern, Enum: field.Enum, Env: field.Env}
+		if field.Map {
+			property = &JSONSchema{Type: "object", Description: field.Description, AdditionalProperties: &JSONSchema{Type: "string"}, EnvPrefix: field.Env}
+		}
+		if field.Required {
+			property.MinLength = 1
+			parent.Required = append(parent.Required, name)
+		}
+		parent.Properties[name] = property
+	}
+
+	return root
+}
+
+// GenerateMarkdown generates a markdown reference of all variables, grouped by section
+func GenerateMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Variables\n\n")
+	sb.WriteString("<!-- generated by `normalizeci schema --format markdown`, do not edit -->\n")
+
+	section := ""
+	for _, field := range SchemaFields() {
+		fieldSection, _, nested := strings.Cut(field.Path, ".")
+		if !nested {
+			fieldSection = "common"
+		}
+		if fieldSection != section {
+			section = fieldSection
+			sb.WriteString(fmt.Sprintf("\n## %s\n\n", section))
+			sb.WriteString("| Variable | Path | Required | Validation | Description |\n")
+			sb.WriteString("|----------|------|----------|------------|-------------|\n")
+		}
+
+		env := "`" + field.Env + "`"
+		if field.Map {
+			env = "`" + field.Env + "<NAME>`"
+		}
+		required := ""
+		if field.Required {
+			required = "yes"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s | %s |\n", env, field.Path, required, markdownEscape(strings.Join(field.Rules, ", ")), markdownEscape(field.Description)))
+	}
+
+	return sb.String()
+}
+
+// markdownEscape escapes the pipe character that would end a table cell
+func markdownEscape(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaFields(t *testing.T) {
+	fields := make(map[string]SchemaField)
+	for _, field := range SchemaFields() {
+		fields[field.Path] = field
+	}
+
+	assert.Equal(t, SchemaField{Path: "worker.arch", Env: "NCI_WORKER_ARCH", Description: "The arch of the ci worker. (ie. linux/amd64)", Required: true, Pattern: "^(linux|windows|darwin)/[a-z0-9]+$", Rules: []string{"must be os/arch (e.g. linux/amd64)"}}, fields["worker.arch"])
+	assert.Equal(t, common.PipelineTriggers, fields["pipeline.trigger"].Enum)
+	assert.Equal(t, SchemaField{Path: "pipeline.inputs", Env: "NCI_INPUT_", Map: true}, fields["pipeline.inputs"])
+	assert.False(t, fields["pipeline.url"].Required)
+	assert.Equal(t, "Holds the vcs specific absolute reference name. (ex: `refs/heads/main`)", fields["commit.refVcs"].Description)
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	schema := GenerateJSONSchema()
+
+	assert.Contains(t, schema.Required, "commit")
+	assert.Contains(t, schema.Required, "serviceSlug")
+	commit := schema.Properties["commit"]
+	assert.Contains(t, commit.Required, "hash")
+	assert.NotContains(t, commit.Required, "description")
+	assert.Equal(t, 1, commit.Properties["hash"].MinLength)
+	assert.Equal(t, "NCI_COMMIT_HASH", commit.Properties["hash"].Env)
+	assert.Equal(t, "^[a-z0-9]+(?:-[a-z0-9]+)*$", schema.Properties["project"].Properties["slug"].Pattern)
+	assert.Equal(t, "NCI_INPUT_", schema.Properties["pipeline"].Properties["inputs"].EnvPrefix)
+}
+
+// TestGeneratedDocs ensures the generated documentation matches the spec, run `normalizeci schema` to update it
+func TestGeneratedDocs(t *testing.T) {
+	markdown, err := os.ReadFile("../../../docs/spec/variables.md")
+	assert.NoError(t, err)
+	assert.Equal(t, GenerateMarkdown(), string(markdown), "docs/spec/variables.md is outdated, run: normalizeci schema --format markdown -o docs/spec/variables.md")
+
+	schema, err := json.MarshalIndent(GenerateJSONSchema(), "", "  ")
+	assert.NoError(t, err)
+	content, err := os.ReadFile("../../../docs/spec/schema.json")
+	assert.NoError(t, err)
+	assert.Equal(t, string(schema)+"\n", string(content), "docs/spec/schema.json is outdated, run: normalizeci schema --format jsonschema -o docs/spec/schema.json")
+}
@@ -1,92 +1,92 @@
 package v1
 
 type Spec struct {
-	Found        string `env:"NCI" validate:"required"`                      // Will be set the true, if the variables have been normalized. (this script)
-	Version      string `env:"NCI_VERSION" validate:"required"`              // The revision of nci that was used to generate the normalized variables.
-	ServiceName  string `env:"NCI_SERVICE_NAME" validate:"required"`         // The commercial name of the used ci service. (e.g. GitLab CI, Travis CI, CircleCI, Jenkins)
-	ServiceSlug  string `env:"NCI_SERVICE_SLUG" validate:"required,is-slug"` // The commercial name normalized as slug for use in scripts, will not be changed.
-	Worker       Worker
-	Pipeline     Pipeline
-	Repository   Repository
-	Project      Project
-	Commit       Commit
-	MergeRequest MergeRequest
-	Flags        Flags
+	Found        string       `env:"NCI" json:"found" yaml:"found" validate:"required"`                                  // Will be set the true, if the variables have been normalized. (this script)
+	Version      string       `env:"NCI_VERSION" json:"version" yaml:"version" validate:"required"`                      // The revision of nci that was used to generate the normalized variables.
+	ServiceName  string       `env:"NCI_SERVICE_NAME" json:"serviceName" yaml:"serviceName" validate:"required"`         // The commercial name of the used ci service. (e.g. GitLab CI, Travis CI, CircleCI, Jenkins)
+	ServiceSlug  string       `env:"NCI_SERVICE_SLUG" json:"serviceSlug" yaml:"serviceSlug" validate:"required,is-slug"` // The commercial name normalized as slug for use in scripts, will not be changed.
+	Worker       Worker       `json:"worker" yaml:"worker"`
+	Pipeline     Pipeline     `json:"pipeline" yaml:"pipeline"`
+	Repository   Repository   `json:"repository" yaml:"repository"`
+	Project      Project      `json:"project" yaml:"project"`
+	Commit       Commit       `json:"commit" yaml:"commit"`
+	MergeRequest MergeRequest `json:"mergeRequest" yaml:"mergeRequest"`
+	Flags        Flags        `json:"flags" yaml:"flags"`
 }
 
 type Worker struct {
-	Id      string `env:"NCI_WORKER_ID" validate:"required"`   // A unique id of the ci worker.
-	Name    string `env:"NCI_WORKER_NAME" validate:"required"` // The human-readable name of the ci worker.
-	Type    string `env:"NCI_WORKER_TYPE" validate:"required"`
-	OS      string `env:"NCI_WORKER_OS"`                               // Worker OS or OS Image
-	Version string `env:"NCI_WORKER_VERSION" validate:"required"`      // The version of the ci worker.
-	Arch    string `env:"NCI_WORKER_ARCH" validate:"required,is-arch"` // The arch of the ci worker. (ie. linux/amd64)
+	Id      string `env:"NCI_WORKER_ID" json:"id" yaml:"id" validate:"required"`       // A unique id of the ci worker.
+	Name    string `env:"NCI_WORKER_NAME" json:"name" yaml:"name" validate:"required"` // The human-readable name of the ci worker.
+	Type    string `env:"NCI_WORKER_TYPE" json:"type" yaml:"type" validate:"required"`
+	OS      string `env:"NCI_WORKER_OS" json:"os" yaml:"os"`                                    // Worker OS or OS Image
+	Version string `env:"NCI_WORKER_VERSION" json:"version" yaml:"version" validate:"required"` // The version of the ci worker.
+	Arch    string `env:"NCI_WORKER_ARCH" json:"arch" yaml:"arch" validate:"required,is-arch"`  // The arch of the ci worker. (ie. linux/amd64)
 }
 
 type Pipeline struct {
-	Id           string            `env:"NCI_PIPELINE_ID" validate:"required"`
-	Trigger      string            `env:"NCI_PIPELINE_TRIGGER" validate:"required,oneof=cli manual push trigger api schedule pull_request build"` // What triggered the pipeline. (ie. manual/push/trigger/api/schedule/pull_request/build)
-	StageId      string            `env:"NCI_PIPELINE_STAGE_ID"`
-	StageName    string            `env:"NCI_PIPELINE_STAGE_NAME" validate:"required"`         // Human-readable name of the current stage.
-	StageSlug    string            `env:"NCI_PIPELINE_STAGE_SLUG" validate:"required,is-slug"` // Slug of the current stage.
-	JobId        string            `env:"NCI_PIPELINE_JOB_ID"`
-	JobName      string            `env:"NCI_PIPELINE_JOB_NAME" validate:"required"`         // Human-readable name of the current job.
-	JobSlug      string            `env:"NCI_PIPELINE_JOB_SLUG" validate:"required,is-slug"` // Slug of the current job.
-	JobStartedAt string            `env:"NCI_PIPELINE_JOB_STARTED_AT" validate:"required"`   // Timestamp when the job started.
-	Attempt      string            `env:"NCI_PIPELINE_ATTEMPT" validate:"number"`            // The current attempt number of the pipeline.
-	ConfigFile   string            `env:"NCI_PIPELINE_CONFIG_FILE"`                          // Pipeline Config File
-	Url          string            `env:"NCI_PIPELINE_URL"`                                  // Pipeline URL
-	Input        map[string]string `env-prefix:"NCI_INPUT_"`
+	Id           string            `env:"NCI_PIPELINE_ID" json:"id" yaml:"id" validate:"required"`
+	Trigger      string            `env:"NCI_PIPELINE_TRIGGER" json:"trigger" yaml:"trigger" validate:"required,oneof=cli manual push merge_request schedule build unknown"` // What triggered the pipeline. (ie. cli/manual/push/merge_request/schedule/build/unknown)
+	StageId      string            `env:"NCI_PIPELINE_STAGE_ID" json:"stageId" yaml:"stageId"`
+	StageName    string            `env:"NCI_PIPELINE_STAGE_NAME" json:"stageName" yaml:"stageName" validate:"required"`         // Human-readable name of the current stage.
+	StageSlug    string            `env:"NCI_PIPELINE_STAGE_SLUG" json:"stageSlug" yaml:"stageSlug" validate:"required,is-slug"` // Slug of the current stage.
+	JobId        string            `env:"NCI_PIPELINE_JOB_ID" json:"jobId" yaml:"jobId"`
+	JobName      string            `env:"NCI_PIPELINE_JOB_NAME" json:"jobName" yaml:"jobName" validate:"required"`                 // Human-readable name of the current job.
+	JobSlug      string            `env:"NCI_PIPELINE_JOB_SLUG" json:"jobSlug" yaml:"jobSlug" validate:"required,is-slug"`         // Slug of the current job.
+	JobStartedAt string            `env:"NCI_PIPELINE_JOB_STARTED_AT" json:"jobStartedAt" yaml:"jobStartedAt" validate:"required"` // Timestamp when the job started.
+	Attempt      string            `env:"NCI_PIPELINE_ATTEMPT" json:"attempt" yaml:"attempt" validate:"number"`                    // The current attempt number of the pipeline.
+	ConfigFile   string            `env:"NCI_PIPELINE_CONFIG_FILE" json:"configFile" yaml:"configFile"`                            // Pipeline Config File
+	Url          string            `env:"NCI_PIPELINE_URL" json:"url" yaml:"url"`                                                  // Pipeline URL
+	Input        map[string]string `env-prefix:"NCI_INPUT_" json:"inputs,omitempty" yaml:"inputs,omitempty"`
 }
 
 type Repository struct {
-	Kind       string `env:"NCI_REPOSITORY_KIND" validate:"required"`        //  The used version control system. (git)
-	Remote     string `env:"NCI_REPOSITORY_REMOTE" validate:"required"`      // The remote url pointing at the repository. (git remote url or `local` if no remote was found)
-	HostServer string `env:"NCI_REPOSITORY_HOST_SERVER" validate:"required"` // Host of the git repository server, for example github.com
-	HostType   string `env:"NCI_REPOSITORY_HOST_TYPE" validate:"required"`   // Type of the git repository server (github, gitlab, ...)
-	Status     string `env:"NCI_REPOSITORY_STATUS" validate:"required"`      // The repository status (dirty, clean)
+	Kind       string `env:"NCI_REPOSITORY_KIND" json:"kind" yaml:"kind" validate:"required"`                    //  The used version control system. (git)
+	Remote     string `env:"NCI_REPOSITORY_REMOTE" json:"remote" yaml:"remote" validate:"required"`              // The remote url pointing at the repository. (git remote url or `local` if no remote was found)
+	HostServer string `env:"NCI_REPOSITORY_HOST_SERVER" json:"hostServer" yaml:"hostServer" validate:"required"` // Host of the git repository server, for example github.com
+	HostType   string `env:"NCI_REPOSITORY_HOST_TYPE" json:"hostType" yaml:"hostType" validate:"required"`       // Type of the git repository server (github, gitlab, ...)
+	Status     string `env:"NCI_REPOSITORY_STATUS" json:"status" yaml:"status" validate:"required"`              // The repository status (dirty, clean)
 }
 
 type Project struct {
-	Id            string `env:"NCI_PROJECT_ID" validate:"required"`           // Unique project id, can be used in deployments.
-	Name          string `env:"NCI_PROJECT_NAME" validate:"required"`         // Unique project id, can be used in deployments.
-	Path          string `env:"NCI_PROJECT_PATH" validate:"required"`         // Path of the Namespace and the project
-	Slug          string `env:"NCI_PROJECT_SLUG" validate:"required,is-slug"` // Project slug, that can be used in deployments.
-	Description   string `env:"NCI_PROJECT_DESCRIPTION"`                      // The project description.
-	Topics        string `env:"NCI_PROJECT_TOPICS"`                           // The topics / tags of the project.
-	IssueUrl      string `env:"NCI_PROJECT_ISSUE_URL"`                        // A template for links to issues, contains a `{ID}` placeholder.
-	Stargazers    string `env:"NCI_PROJECT_STARGAZERS"`                       // The number of people who `follow` / `bookmarked` the project.
-	Forks         string `env:"NCI_PROJECT_FORKS"`                            // The number of forks of the project.
-	Dir           string `env:"NCI_PROJECT_DIR" validate:"required"`          // Project directory on the local filesystem.
-	Url           string `env:"NCI_PROJECT_URL"`                              // Project URL
-	DefaultBranch string `env:"NCI_PROJECT_DEFAULT_BRANCH"`                   // The default branch
+	Id            string `env:"NCI_PROJECT_ID" json:"id" yaml:"id" validate:"required"`               // Unique project id, can be used in deployments.
+	Name          string `env:"NCI_PROJECT_NAME" json:"name" yaml:"name" validate:"required"`         // Unique project id, can be used in deployments.
+	Path          string `env:"NCI_PROJECT_PATH" json:"path" yaml:"path" validate:"required"`         // Path of the Namespace and the project
+	Slug          string `env:"NCI_PROJECT_SLUG" json:"slug" yaml:"slug" validate:"required,is-slug"` // Project slug, that can be used in deployments.
+	Description   string `env:"NCI_PROJECT_DESCRIPTION" json:"description" yaml:"description"`        // The project description.
+	Topics        string `env:"NCI_PROJECT_TOPICS" json:"topics" yaml:"topics"`                       // The topics / tags of the project.
+	IssueUrl      string `env:"NCI_PROJECT_ISSUE_URL" json:"issueUrl" yaml:"issueUrl"`                // A template for links to issues, contains a `{ID}` placeholder.
+	Stargazers    string `env:"NCI_PROJECT_STARGAZERS" json:"stargazers" yaml:"stargazers"`           // The number of people who `follow` / `bookmarked` the project.
+	Forks         string `env:"NCI_PROJECT_FORKS" json:"forks" yaml:"forks"`                          // The number of forks of the project.
+	Dir           string `env:"NCI_PROJECT_DIR" json:"dir" yaml:"dir" validate:"required"`            // Project directory on the local filesystem.
+	Url           string `env:"NCI_PROJECT_URL" json:"url" yaml:"url"`                                // Project URL
+	DefaultBranch string `env:"NCI_PROJECT_DEFAULT_BRANCH" json:"defaultBranch" yaml:"defaultBranch"` // The default branch
 }
 
 type Commit struct {
-	RefType        string `env:"NCI_COMMIT_REF_TYPE" validate:"required"`        // The reference type. (branch / tag)
-	RefName        string `env:"NCI_COMMIT_REF_NAME" validate:"required"`        // Human-readable name of the current repository reference.
-	RefPath        string `env:"NCI_COMMIT_REF_PATH" validate:"required"`        // Combination of the ref type and ref name. (tag/v1.0.0 or branch/main)
-	RefSlug        string `env:"NCI_COMMIT_REF_SLUG" validate:"required"`        // Slug of the current repository reference.
-	RefVCS         string `env:"NCI_COMMIT_REF_VCS" validate:"required"`         // Holds the vcs specific absolute reference name. (ex: `refs/heads/main`)// Release version of the artifact, without leading `v` or `/` - should be in format `x.y.z` or `feature-abc`.
-	RefRelease     string `env:"NCI_COMMIT_REF_RELEASE" validate:"required"`     // Release version of the artifact, without leading `v` or `/` - should be in format `x.y.z` or `feature-abc`.
-	HashShort      string `env:"NCI_COMMIT_HASH_SHORT" validate:"required"`      // A short form of the unique commit hash. (8 chars)
-	Hash           string `env:"NCI_COMMIT_HASH" validate:"required"`            //  A unique hash, that each commit gets.
-	AuthorName     string `env:"NCI_COMMIT_AUTHOR_NAME" validate:"required"`     // author name
-	AuthorEmail    string `env:"NCI_COMMIT_AUTHOR_EMAIL" validate:"required"`    // author email
-	CommitterName  string `env:"NCI_COMMIT_COMMITTER_NAME" validate:"required"`  // committer name
-	CommitterEmail string `env:"NCI_COMMIT_COMMITTER_EMAIL" validate:"required"` // committer email
-	Title          string `env:"NCI_COMMIT_TITLE" validate:"required"`           // The title of the latest commit on the current reference.
-	Description    string `env:"NCI_COMMIT_DESCRIPTION"`                         // The description of the latest commit on the current reference.
-	Count          string `env:"NCI_COMMIT_COUNT" validate:"required"`           // The total amount of commits inside the current reference, can be used as build number.
+	RefType        string `env:"NCI_COMMIT_REF_TYPE" json:"refType" yaml:"refType" validate:"required"`                      // The reference type. (branch / tag)
+	RefName        string `env:"NCI_COMMIT_REF_NAME" json:"refName" yaml:"refName" validate:"required"`                      // Human-readable name of the current repository reference.
+	RefPath        string `env:"NCI_COMMIT_REF_PATH" json:"refPath" yaml:"refPath" validate:"required"`                      // Combination of the ref type and ref name. (tag/v1.0.0 or branch/main)
+	RefSlug        string `env:"NCI_COMMIT_REF_SLUG" json:"refSlug" yaml:"refSlug" validate:"required"`                      // Slug of the current repository reference.
+	RefVCS         string `env:"NCI_COMMIT_REF_VCS" json:"refVcs" yaml:"refVcs" validate:"required"`                         // Holds the vcs specific absolute reference name. (ex: `refs/heads/main`)// Release version of the artifact, without leading `v` or `/` - should be in format `x.y.z` or `feature-abc`.
+	RefRelease     string `env:"NCI_COMMIT_REF_RELEASE" json:"refRelease" yaml:"refRelease" validate:"required"`             // Release version of the artifact, without leading `v` or `/` - should be in format `x.y.z` or `feature-abc`.
+	HashShort      string `env:"NCI_COMMIT_HASH_SHORT" json:"hashShort" yaml:"hashShort" validate:"required"`                // A short form of the unique commit hash. (8 chars)
+	Hash           string `env:"NCI_COMMIT_HASH" json:"hash" yaml:"hash" validate:"required"`                                //  A unique hash, that each commit gets.
+	AuthorName     string `env:"NCI_COMMIT_AUTHOR_NAME" json:"authorName" yaml:"authorName" validate:"required"`             // author name
+	AuthorEmail    string `env:"NCI_COMMIT_AUTHOR_EMAIL" json:"authorEmail" yaml:"authorEmail" validate:"required"`          // author email
+	CommitterName  string `env:"NCI_COMMIT_COMMITTER_NAME" json:"committerName" yaml:"committerName" validate:"required"`    // committer name
+	CommitterEmail string `env:"NCI_COMMIT_COMMITTER_EMAIL" json:"committerEmail" yaml:"committerEmail" validate:"required"` // committer email
+	Title          string `env:"NCI_COMMIT_TITLE" json:"title" yaml:"title" validate:"required"`                             // The title of the latest commit on the current reference.
+	Description    string `env:"NCI_COMMIT_DESCRIPTION" json:"description" yaml:"description"`                               // The description of the latest commit on the current reference.
+	Count          string `env:"NCI_COMMIT_COUNT" json:"count" yaml:"count" validate:"required"`                             // The total amount of commits inside the current reference, can be used as build number.
 }
 
 type MergeRequest struct {
-	Id               string `env:"NCI_MERGE_REQUEST_ID"`
-	Title            string `env:"NCI_MERGE_REQUEST_TITLE"`
-	SourceBranchName string `env:"NCI_MERGE_REQUEST_SOURCE_BRANCH_NAME"`
-	SourceHash       string `env:"NCI_MERGE_REQUEST_SOURCE_HASH"`
-	TargetBranchName string `env:"NCI_MERGE_REQUEST_TARGET_BRANCH_NAME"`
-	TargetHash       string `env:"NCI_MERGE_REQUEST_TARGET_HASH"`
+	Id               string `env:"NCI_MERGE_REQUEST_ID" json:"id" yaml:"id"`
+	Title            string `env:"NCI_MERGE_REQUEST_TITLE" json:"title" yaml:"title"`
+	SourceBranchName string `env:"NCI_MERGE_REQUEST_SOURCE_BRANCH_NAME" json:"sourceBranchName" yaml:"sourceBranchName"`
+	SourceHash       string `env:"NCI_MERGE_REQUEST_SOURCE_HASH" json:"sourceHash" yaml:"sourceHash"`
+	TargetBranchName string `env:"NCI_MERGE_REQUEST_TARGET_BRANCH_NAME" json:"targetBranchName" yaml:"targetBranchName"`
+	TargetHash       string `env:"NCI_MERGE_REQUEST_TARGET_HASH" json:"targetHash" yaml:"targetHash"`
 
 	/** extend with
 	CI_MERGE_REQUEST_ID	11.6	all	The instance-level ID of the merge request. This is a unique ID across all projects on GitLab.
@@ -99,7 +99,7 @@ type MergeRequest struct {
 }
 
 type Flags struct {
-	DeployFreeze string `env:"NCI_DEPLOY_FREEZE"`
+	DeployFreeze string `env:"NCI_DEPLOY_FREEZE" json:"deployFreeze" yaml:"deployFreeze"`
 }
 
 // Create creates a new Spec
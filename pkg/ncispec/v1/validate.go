@@ -2,6 +2,8 @@ package v1
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/cidverse/normalizeci/pkg/ncispec/common"
 	"github.com/go-playground/validator/v10"
@@ -21,9 +23,34 @@ func (spec Spec) Validate() []common.ValidationError {
 
 		validationErrors := err.(validator.ValidationErrors)
 		for _, err := range validationErrors {
-			errors = append(errors, common.ValidationError{Field: err.Field(), Value: fmt.Sprintf("%v", err.Value()), Description: err.Tag()})
+			errors = append(errors, common.ValidationError{
+				Field:       err.Field(),
+				Env:         envName(err.StructNamespace()),
+				Value:       fmt.Sprintf("%v", err.Value()),
+				Description: err.Tag(),
+				Message:     common.ValidationMessage(err.Tag(), err.Param()),
+			})
 		}
 	}
 
 	return errors
 }
+
+// envName resolves the env tag of a field by its namespace, e.g. Spec.Commit.Hash
+func envName(namespace string) string {
+	fieldType := reflect.TypeOf(Spec{})
+	var field reflect.StructField
+	for _, name := range strings.Split(namespace, ".")[1:] {
+		if fieldType.Kind() != reflect.Struct {
+			return ""
+		}
+		f, ok := fieldType.FieldByName(name)
+		if !ok {
+			return ""
+		}
+		field = f
+		fieldType = f.Type
+	}
+
+	return field.Tag.Get("env")
+}
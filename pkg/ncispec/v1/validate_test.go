@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	spec := Create("GitHub Actions", "github-actions")
+	spec.Worker.Arch = "amd64"
+	spec.Pipeline.Trigger = "deployment"
+
+	errors := make(map[string]common.ValidationError)
+	for _, err := range spec.Validate() {
+		errors[err.Env] = err
+	}
+
+	assert.Equal(t, common.ValidationError{Field: "Hash", Env: "NCI_COMMIT_HASH", Value: "", Description: "required", Message: "is required"}, errors["NCI_COMMIT_HASH"])
+	assert.True(t, errors["NCI_COMMIT_HASH"].IsMissing())
+	assert.Equal(t, "must be os/arch (e.g. linux/amd64)", errors["NCI_WORKER_ARCH"].Message)
+	assert.False(t, errors["NCI_WORKER_ARCH"].IsMissing())
+	assert.Equal(t, "must be one of: cli, manual, push, merge_request, schedule, build, unknown", errors["NCI_PIPELINE_TRIGGER"].Message)
+	assert.NotContains(t, errors, "")
+}
+
+func TestValidate_Triggers(t *testing.T) {
+	field, _ := reflect.TypeOf(Pipeline{}).FieldByName("Trigger")
+	assert.Equal(t, "required,oneof="+strings.Join(common.PipelineTriggers, " "), field.Tag.Get("validate"))
+
+	for _, trigger := range common.PipelineTriggers {
+		spec := Create("GitHub Actions", "github-actions")
+		spec.Pipeline.Trigger = trigger
+
+		for _, err := range spec.Validate() {
+			assert.NotEqual(t, "NCI_PIPELINE_TRIGGER", err.Env, trigger)
+		}
+	}
+}
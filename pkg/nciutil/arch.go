@@ -0,0 +1,31 @@
+package nciutil
+
+import (
+	"strings"
+)
+
+// SplitArch splits a worker arch (ie. linux/amd64) into the operating system and cpu architecture
+func SplitArch(arch string) (string, string) {
+	parts := strings.SplitN(arch, "/", 2)
+	if len(parts) != 2 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
+
+// ToRunnerArch converts the go architecture name into the notation used by GitHub Actions and Azure DevOps (X64, X86, ARM, ARM64)
+func ToRunnerArch(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return "X64"
+	case "386":
+		return "X86"
+	case "arm":
+		return "ARM"
+	case "arm64":
+		return "ARM64"
+	default:
+		return goarch
+	}
+}
@@ -0,0 +1,34 @@
+package nciutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitArch(t *testing.T) {
+	tests := []struct {
+		arch string
+		os   string
+		cpu  string
+	}{
+		{"linux/amd64", "linux", "amd64"},
+		{"windows/arm64", "windows", "arm64"},
+		{"darwin", "darwin", ""},
+		{"", "", ""},
+	}
+
+	for _, test := range tests {
+		os, cpu := SplitArch(test.arch)
+		assert.Equal(t, test.os, os, test.arch)
+		assert.Equal(t, test.cpu, cpu, test.arch)
+	}
+}
+
+func TestToRunnerArch(t *testing.T) {
+	assert.Equal(t, "X64", ToRunnerArch("amd64"))
+	assert.Equal(t, "X86", ToRunnerArch("386"))
+	assert.Equal(t, "ARM", ToRunnerArch("arm"))
+	assert.Equal(t, "ARM64", ToRunnerArch("arm64"))
+	assert.Equal(t, "riscv64", ToRunnerArch("riscv64"))
+}
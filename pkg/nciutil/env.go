@@ -0,0 +1,60 @@
+package nciutil
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// envNameRegex matches environment variable names that can be used safely in all supported shells
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// lineBreakEncoder encodes line breaks for formats that only support single-line values
+var lineBreakEncoder = strings.NewReplacer(
+	"\r", `\r`,
+	"\n", `\n`,
+)
+
+// SortedEnvKeys returns the keys of the environment in a stable order, after checking that they are valid variable names and the values can be stored in an environment variable
+func SortedEnvKeys(env map[string]string) ([]string, error) {
+	keys := make([]string, 0, len(env))
+	for key, value := range env {
+		if !envNameRegex.MatchString(key) {
+			return nil, fmt.Errorf("invalid environment variable name: %q", key)
+		}
+		if strings.ContainsRune(value, 0) {
+			return nil, fmt.Errorf("value of %s contains a NUL byte", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// QuotePosix wraps the value in single quotes, nothing is expanded within single quotes and embedded single quotes are closed, escaped and reopened
+func QuotePosix(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// EncodeLineBreaks replaces line breaks with the escape sequences \r and \n
+func EncodeLineBreaks(value string) string {
+	return lineBreakEncoder.Replace(value)
+}
+
+// AppendToFile appends the content to the file, the file is created if it doesn't exist
+func AppendToFile(file string, content string) error {
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	if _, err = f.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+
+	return nil
+}
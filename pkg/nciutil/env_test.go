@@ -0,0 +1,31 @@
+package nciutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedEnvKeys(t *testing.T) {
+	keys, err := SortedEnvKeys(map[string]string{"NCI_B": "b", "_A": "a", "nci_c": "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NCI_B", "_A", "nci_c"}, keys)
+
+	_, err = SortedEnvKeys(map[string]string{"1NCI": "a"})
+	assert.Error(t, err)
+
+	_, err = SortedEnvKeys(map[string]string{"NCI=A": "a"})
+	assert.Error(t, err)
+
+	_, err = SortedEnvKeys(map[string]string{"NCI_A": "a\x00"})
+	assert.Error(t, err)
+}
+
+func TestQuotePosix(t *testing.T) {
+	assert.Equal(t, `'plain'`, QuotePosix("plain"))
+	assert.Equal(t, `'it'\''s $(id)'`, QuotePosix("it's $(id)"))
+}
+
+func TestEncodeLineBreaks(t *testing.T) {
+	assert.Equal(t, `a\r\nb\nc`, EncodeLineBreaks("a\r\nb\nc"))
+}
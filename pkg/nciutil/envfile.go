@@ -0,0 +1,135 @@
+package nciutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseEnvFile parses variables in the export and dotenv formats.
+// Values can be single-quoted (literal), double-quoted (with \n, \r, \t, \\, \", \$ escapes) or unquoted, adjacent quoted parts are concatenated as in a POSIX shell.
+// Empty lines and comments are skipped, an optional `export ` prefix is removed.
+func ParseEnvFile(content string) (map[string]string, error) {
+	result := make(map[string]string)
+	runes := []rune(strings.ReplaceAll(content, "\r\n", "\n"))
+	line := 1
+
+	for i := 0; i < len(runes); {
+		// skip whitespace and comments
+		switch runes[i] {
+		case '\n':
+			line++
+			i++
+			continue
+		case ' ', '\t':
+			i++
+			continue
+		case '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			continue
+		}
+
+		// key
+		start := i
+		for i < len(runes) && runes[i] != '=' && runes[i] != '\n' {
+			i++
+		}
+		key := strings.TrimSpace(string(runes[start:i]))
+		key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+		if i >= len(runes) || runes[i] != '=' || !envNameRegex.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid variable definition %q", line, string(runes[start:i]))
+		}
+		i++
+		for i < len(runes) && (runes[i] == ' ' || runes[i] == '\t') {
+			i++
+		}
+
+		// value
+		var value strings.Builder
+		startLine := line
+	value:
+		for i < len(runes) {
+			switch r := runes[i]; r {
+			case '\n':
+				break value
+			case ' ', '\t':
+				// whitespace within an unquoted value is kept, trailing whitespace and comments are dropped
+				rest := i
+				for rest < len(runes) && (runes[rest] == ' ' || runes[rest] == '\t') {
+					rest++
+				}
+				if rest >= len(runes) || runes[rest] == '\n' || runes[rest] == '#' {
+					i = rest
+					for i < len(runes) && runes[i] != '\n' {
+						i++
+					}
+					break value
+				}
+				value.WriteString(string(runes[i:rest]))
+				i = rest
+			case '\'':
+				end := i + 1
+				for end < len(runes) && runes[end] != '\'' {
+					if runes[end] == '\n' {
+						line++
+					}
+					end++
+				}
+				if end >= len(runes) {
+					return nil, fmt.Errorf("line %d: unterminated single-quoted value of %s", startLine, key)
+				}
+				value.WriteString(string(runes[i+1 : end]))
+				i = end + 1
+			case '"':
+				i++
+				for {
+					if i >= len(runes) {
+						return nil, fmt.Errorf("line %d: unterminated double-quoted value of %s", startLine, key)
+					}
+					if runes[i] == '"' {
+						i++
+						break
+					}
+					if runes[i] == '\\' && i+1 < len(runes) {
+						i++
+						switch runes[i] {
+						case 'n':
+							value.WriteRune('\n')
+						case 'r':
+							value.WriteRune('\r')
+						case 't':
+							value.WriteRune('\t')
+						case '\\', '"', '$', '`':
+							value.WriteRune(runes[i])
+						default:
+							value.WriteRune('\\')
+							value.WriteRune(runes[i])
+						}
+						i++
+						continue
+					}
+					if runes[i] == '\n' {
+						line++
+					}
+					value.WriteRune(runes[i])
+					i++
+				}
+			case '\\':
+				if i+1 < len(runes) && runes[i+1] != '\n' {
+					value.WriteRune(runes[i+1])
+					i += 2
+				} else {
+					i++
+				}
+			default:
+				value.WriteRune(r)
+				i++
+			}
+		}
+
+		result[key] = value.String()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package nciutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	env, err := ParseEnvFile("# comment\n\n" +
+		"export NCI_A=" + QuotePosix("it's\nmultiline $HOME") + "\n" +
+		"NCI_B='single'\n" +
+		"NCI_C=\"double \\\"quoted\\\"\\nwith escapes \\$HOME\"\n" +
+		"NCI_D=plain value # trailing comment\r\n" +
+		"NCI_E=\n" +
+		"  NCI_F = spaced\n" +
+		"NCI_G=a#b\n")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"NCI_A": "it's\nmultiline $HOME",
+		"NCI_B": "single",
+		"NCI_C": "double \"quoted\"\nwith escapes $HOME",
+		"NCI_D": "plain value",
+		"NCI_E": "",
+		"NCI_F": "spaced",
+		"NCI_G": "a#b",
+	}, env)
+}
+
+func TestParseEnvFile_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"NCI_A",
+		"NCI A=value",
+		"NCI_A='unterminated",
+		"NCI_A=\"unterminated",
+		"NCI_A=ok\n$(rm)=x",
+	} {
+		_, err := ParseEnvFile(content)
+		assert.Error(t, err, content)
+	}
+
+	_, err := ParseEnvFile("NCI_A=ok\nNCI_B='x\ny\nNCI_C=z")
+	assert.ErrorContains(t, err, "line 2")
+}
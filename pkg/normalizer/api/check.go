@@ -0,0 +1,13 @@
+package api
+
+// CheckVariables is implemented by normalizers that detect the ci service by environment variables
+type CheckVariables interface {
+	// GetCheckVariables returns the names of the environment variables evaluated by Check
+	GetCheckVariables() []string
+}
+
+// CheckDescriber is implemented by normalizers that don't detect the ci service by environment variables
+type CheckDescriber interface {
+	// DescribeCheck returns what Check evaluates in the environment, shown by the detect command
+	DescribeCheck(env map[string]string) string
+}
@@ -14,9 +14,11 @@ import (
 type Normalizer interface {
 	GetName() string
 	GetSlug() string
+	GetVersion() string
 	Check(env map[string]string) bool
 	Normalize(env map[string]string) (v1.Spec, error)
 	Denormalize(spec v1.Spec) (map[string]string, error)
+	SupportsDenormalize() bool
 }
 
 // GetMachineEnvironment returns a map with all environment variables set on the machine
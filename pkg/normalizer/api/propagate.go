@@ -0,0 +1,26 @@
+package api
+
+// Propagator is implemented by normalizers that can pass variables to the subsequent steps of the pipeline
+type Propagator interface {
+	// Propagate makes the variables available to subsequent steps, the returned content must be written to stdout (e.g. workflow commands)
+	Propagate(env map[string]string, vars map[string]string, opts PropagateOptions) (string, error)
+}
+
+// PropagateOptions configures how variables are propagated
+type PropagateOptions struct {
+	Secrets            []string // Secrets holds the names of variables that must be masked in the job log
+	File               string   // File is the report file for systems that pass variables as artifact (GitLab dotenv)
+	DotenvMaxVariables int      // DotenvMaxVariables is the variable limit of the GitLab dotenv report, zero uses the GitLab.com limit
+	DotenvMaxSize      int      // DotenvMaxSize is the size limit of the GitLab dotenv report in bytes, zero uses the GitLab.com limit
+}
+
+// IsSecret returns true if the variable is flagged as secret
+func (o PropagateOptions) IsSecret(name string) bool {
+	for _, secret := range o.Secrets {
+		if secret == name {
+			return true
+		}
+	}
+
+	return false
+}
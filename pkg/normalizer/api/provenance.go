@@ -0,0 +1,11 @@
+package api
+
+import (
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+)
+
+// ProvenanceNormalizer is implemented by normalizers that record where the value of each field came from
+type ProvenanceNormalizer interface {
+	// NormalizeWithProvenance works like Normalize, but additionally returns the source of each field
+	NormalizeWithProvenance(env map[string]string) (v1.Spec, v1.Provenance, error)
+}
@@ -0,0 +1,7 @@
+package api
+
+// SummaryWriter is implemented by normalizers that can display a markdown summary for the job
+type SummaryWriter interface {
+	// WriteSummary publishes the markdown summary, the returned content must be written to stdout (e.g. logging commands)
+	WriteSummary(env map[string]string, markdown string) (string, error)
+}
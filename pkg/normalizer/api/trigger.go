@@ -0,0 +1,7 @@
+package api
+
+// TriggerNormalizer is implemented by normalizers that map the native events of the ci service to the pipeline triggers
+type TriggerNormalizer interface {
+	// NormalizeTrigger returns the pipeline trigger for a native event name, unknown if the event is not supported
+	NormalizeTrigger(event string) string
+}
@@ -17,6 +17,21 @@ func (n Normalizer) GetSlug() string {
 	return n.slug
 }
 
+// GetVersion returns the version of the normalizer
+func (n Normalizer) GetVersion() string {
+	return n.version
+}
+
+// GetCheckVariables returns the names of the environment variables evaluated by Check
+func (n Normalizer) GetCheckVariables() []string {
+	return []string{"CI_SERVICE_NAME"}
+}
+
+// SupportsDenormalize returns true if the normalizer can generate the native environment from the common format
+func (n Normalizer) SupportsDenormalize() bool {
+	return true
+}
+
 // Check if this package can handle the current environment
 func (n Normalizer) Check(env map[string]string) bool {
 	return env["CI_SERVICE_NAME"] == "appveyor"
@@ -1,9 +1,87 @@
 package appveyor
 
 import (
+	"regexp"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
 	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
 )
 
+// pipelineURLRegex matches the pipeline url generated by Normalize (<server>/project/<account>/<project>/builds/<id>)
+var pipelineURLRegex = regexp.MustCompile(`^(.+)/project/([^/]+)/([^/]+)/builds/([^/]+)$`)
+
+// Denormalize generates the AppVeyor environment variables from the common format
 func (n Normalizer) Denormalize(spec v1.Spec) (map[string]string, error) {
-	return make(map[string]string), nil
+	data := make(map[string]string)
+
+	// common
+	data["CI"] = "True"
+	data["APPVEYOR"] = "True"
+	data["CI_SERVICE_NAME"] = "appveyor"
+
+	// worker
+	data["APPVEYOR_BUILD_WORKER_IMAGE"] = spec.Worker.OS
+
+	// pipeline
+	data["APPVEYOR_BUILD_ID"] = spec.Pipeline.Id
+	data["APPVEYOR_JOB_ID"] = spec.Pipeline.JobId
+	data["APPVEYOR_JOB_NAME"] = spec.Pipeline.JobName
+	data["APPVEYOR_JOB_NUMBER"] = spec.Pipeline.Attempt
+	switch spec.Pipeline.Trigger {
+	case common.PipelineTriggerSchedule:
+		data["APPVEYOR_SCHEDULED_BUILD"] = "True"
+	case common.PipelineTriggerManual, common.PipelineTriggerCLI:
+		data["APPVEYOR_FORCED_BUILD"] = "True"
+	}
+	if match := pipelineURLRegex.FindStringSubmatch(spec.Pipeline.Url); match != nil {
+		data["APPVEYOR_URL"] = match[1]
+		data["APPVEYOR_ACCOUNT_NAME"] = match[2]
+		data["APPVEYOR_PROJECT_SLUG"] = match[3]
+	}
+
+	// repository and project
+	data["APPVEYOR_PROJECT_NAME"] = spec.Project.Name
+	data["APPVEYOR_BUILD_FOLDER"] = spec.Project.Dir
+	data["APPVEYOR_REPO_NAME"] = spec.Project.Path
+	data["APPVEYOR_REPO_PROVIDER"] = appveyorRepositoryProvider(spec.Repository.HostType)
+	data["APPVEYOR_REPO_SCM"] = spec.Repository.Kind
+
+	// commit
+	data["APPVEYOR_REPO_COMMIT"] = spec.Commit.Hash
+	data["APPVEYOR_REPO_COMMIT_AUTHOR"] = spec.Commit.AuthorName
+	data["APPVEYOR_REPO_COMMIT_AUTHOR_EMAIL"] = spec.Commit.AuthorEmail
+	data["APPVEYOR_REPO_COMMIT_MESSAGE"] = spec.Commit.Title
+	data["APPVEYOR_REPO_COMMIT_MESSAGE_EXTENDED"] = spec.Commit.Description
+	data["APPVEYOR_REPO_BRANCH"] = spec.Commit.RefName
+	if spec.Commit.RefType == "tag" {
+		data["APPVEYOR_REPO_TAG"] = "true"
+		data["APPVEYOR_REPO_TAG_NAME"] = spec.Commit.RefName
+	} else {
+		data["APPVEYOR_REPO_TAG"] = "false"
+	}
+
+	// pull request, AppVeyor reports the target branch as APPVEYOR_REPO_BRANCH
+	if spec.MergeRequest.Id != "" {
+		data["APPVEYOR_REPO_BRANCH"] = spec.MergeRequest.TargetBranchName
+		data["APPVEYOR_PULL_REQUEST_NUMBER"] = spec.MergeRequest.Id
+		data["APPVEYOR_PULL_REQUEST_TITLE"] = spec.MergeRequest.Title
+		data["APPVEYOR_PULL_REQUEST_HEAD_REPO_NAME"] = spec.Project.Path
+		data["APPVEYOR_PULL_REQUEST_HEAD_REPO_BRANCH"] = spec.MergeRequest.SourceBranchName
+		data["APPVEYOR_PULL_REQUEST_HEAD_COMMIT"] = spec.MergeRequest.SourceHash
+	}
+
+	return data, nil
+}
+
+func appveyorRepositoryProvider(hostType string) string {
+	switch hostType {
+	case "github":
+		return "gitHub"
+	case "gitlab":
+		return "gitLab"
+	case "bitbucket":
+		return "bitBucket"
+	default:
+		return "git"
+	}
 }
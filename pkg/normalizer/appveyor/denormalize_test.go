@@ -0,0 +1,100 @@
+package appveyor
+
+import (
+	"testing"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/normalizertest"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSpec = v1.Spec{
+	Worker: v1.Worker{
+		OS: "Ubuntu2004",
+	},
+	Pipeline: v1.Pipeline{
+		Id:        "51035157",
+		StageId:   "51035157",
+		StageName: "default",
+		StageSlug: "default",
+		JobId:     "yyrrqc54e997xewx",
+		JobName:   "linux",
+		JobSlug:   "linux",
+		Attempt:   "1",
+		Url:       "https://ci.appveyor.com/project/PhilippHeuer/cienvsamples/builds/51035157",
+	},
+	Repository: v1.Repository{
+		Kind:     "git",
+		HostType: "github",
+	},
+	Project: v1.Project{
+		Name: "cienvsamples",
+		Path: "cidverse/cienvsamples",
+		Dir:  "/home/appveyor/projects/cienvsamples",
+	},
+	Commit: v1.Commit{
+		RefType:   "branch",
+		RefName:   "main",
+		Hash:      "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+		HashShort: "790efd9",
+		Title:     "feat: add gitlab sync job",
+	},
+}
+
+func TestNormalizer_Denormalize(t *testing.T) {
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(testSpec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "True", denormalized["APPVEYOR"])
+	assert.Equal(t, "https://ci.appveyor.com", denormalized["APPVEYOR_URL"])
+	assert.Equal(t, "PhilippHeuer", denormalized["APPVEYOR_ACCOUNT_NAME"])
+	assert.Equal(t, "cienvsamples", denormalized["APPVEYOR_PROJECT_SLUG"])
+	assert.Equal(t, "gitHub", denormalized["APPVEYOR_REPO_PROVIDER"])
+	assert.Equal(t, "main", denormalized["APPVEYOR_REPO_BRANCH"])
+	assert.Equal(t, "false", denormalized["APPVEYOR_REPO_TAG"])
+	assert.Equal(t, "feat: add gitlab sync job", denormalized["APPVEYOR_REPO_COMMIT_MESSAGE"])
+	assert.NotContains(t, denormalized, "APPVEYOR_PULL_REQUEST_NUMBER")
+}
+
+func TestNormalizer_Denormalize_Tag(t *testing.T) {
+	spec := testSpec
+	spec.Commit.RefType = "tag"
+	spec.Commit.RefName = "v1.0.0"
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "true", denormalized["APPVEYOR_REPO_TAG"])
+	assert.Equal(t, "v1.0.0", denormalized["APPVEYOR_REPO_TAG_NAME"])
+}
+
+func TestNormalizer_Denormalize_RoundTrip(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	normalizertest.AssertRoundTrip(t, NewNormalizer(), testSpec, []string{
+		"NCI_WORKER_OS",
+		"NCI_PIPELINE_ID",
+		"NCI_PIPELINE_STAGE_ID",
+		"NCI_PIPELINE_STAGE_NAME",
+		"NCI_PIPELINE_JOB_ID",
+		"NCI_PIPELINE_JOB_NAME",
+		"NCI_PIPELINE_JOB_SLUG",
+		"NCI_PIPELINE_ATTEMPT",
+		"NCI_PIPELINE_URL",
+		"NCI_COMMIT_HASH",
+		"NCI_COMMIT_HASH_SHORT",
+	})
+}
+
+func TestNormalizer_Normalize_ShortCommitHash(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	var normalizer = NewNormalizer()
+	var normalized, err = normalizer.Normalize(map[string]string{"APPVEYOR_REPO_COMMIT": "790e"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "790e", normalized.Commit.HashShort)
+}
@@ -3,6 +3,7 @@ package appveyor
 import (
 	"fmt"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/cidverse/go-vcs/vcsutil"
@@ -15,55 +16,97 @@ import (
 
 // Normalize normalizes the environment variables into the common format
 func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
+	nci, _, err := n.NormalizeWithProvenance(env)
+	return nci, err
+}
+
+// NormalizeWithProvenance normalizes the environment variables into the common format and records the source of each field
+func (n Normalizer) NormalizeWithProvenance(env map[string]string) (v1.Spec, v1.Provenance, error) {
 	nci := v1.Create(n.name, n.slug)
+	prov := v1.Provenance{}
 
 	// worker
 	nci.Worker = v1.Worker{
 		Id:      "0",
 		Name:    "unknown",
 		Type:    "appveyor_hosted_vm",
-		OS:      env["APPVEYOR_BUILD_WORKER_IMAGE"],
+		OS:      prov.Env("worker.os", env, "APPVEYOR_BUILD_WORKER_IMAGE"),
 		Version: "latest",
 		Arch:    runtime.GOOS + "/" + runtime.GOARCH,
 	}
 
 	// pipeline
-	nci.Pipeline.Id = env["APPVEYOR_BUILD_ID"]
-	nci.Pipeline.Trigger = common.PipelineTriggerPush
-	nci.Pipeline.StageId = env["APPVEYOR_BUILD_ID"]
+	nci.Pipeline.Id = prov.Env("pipeline.id", env, "APPVEYOR_BUILD_ID")
+	nci.Pipeline.Trigger = appveyorTriggerNormalize(env)
+	prov.Set("pipeline.trigger", v1.SourceEnv, "APPVEYOR_PULL_REQUEST_NUMBER, APPVEYOR_SCHEDULED_BUILD, APPVEYOR_FORCED_BUILD, APPVEYOR_RE_BUILD")
+	nci.Pipeline.StageId = prov.Env("pipeline.stageId", env, "APPVEYOR_BUILD_ID")
 	nci.Pipeline.StageName = "default"
 	nci.Pipeline.StageSlug = slug.Make("default")
-	nci.Pipeline.JobId = env["APPVEYOR_JOB_ID"]
-	nci.Pipeline.JobName = env["APPVEYOR_JOB_NAME"]
-	nci.Pipeline.JobSlug = slug.Make(env["APPVEYOR_JOB_NAME"])
+	nci.Pipeline.JobId = prov.Env("pipeline.jobId", env, "APPVEYOR_JOB_ID")
+	nci.Pipeline.JobName = prov.Env("pipeline.jobName", env, "APPVEYOR_JOB_NAME")
+	nci.Pipeline.JobSlug = slug.Make(prov.Env("pipeline.jobSlug", env, "APPVEYOR_JOB_NAME"))
 	nci.Pipeline.JobStartedAt = time.Now().Format(time.RFC3339)
-	nci.Pipeline.Attempt = env["APPVEYOR_JOB_NUMBER"]
+	nci.Pipeline.Attempt = prov.Env("pipeline.attempt", env, "APPVEYOR_JOB_NUMBER")
 	nci.Pipeline.Url = fmt.Sprintf("%s/project/%s/%s/builds/%s", env["APPVEYOR_URL"], env["APPVEYOR_ACCOUNT_NAME"], env["APPVEYOR_PROJECT_SLUG"], env["APPVEYOR_BUILD_ID"])
+	prov.Set("pipeline.url", v1.SourceEnv, "APPVEYOR_URL, APPVEYOR_ACCOUNT_NAME, APPVEYOR_PROJECT_SLUG, APPVEYOR_BUILD_ID")
+
+	// pull request, AppVeyor reports the target branch as APPVEYOR_REPO_BRANCH
+	if _, isPullRequest := env["APPVEYOR_PULL_REQUEST_NUMBER"]; isPullRequest {
+		nci.MergeRequest.Id = prov.Env("mergeRequest.id", env, "APPVEYOR_PULL_REQUEST_NUMBER")
+		nci.MergeRequest.Title = prov.Env("mergeRequest.title", env, "APPVEYOR_PULL_REQUEST_TITLE")
+		nci.MergeRequest.SourceBranchName = prov.Env("mergeRequest.sourceBranchName", env, "APPVEYOR_PULL_REQUEST_HEAD_REPO_BRANCH")
+		nci.MergeRequest.SourceHash = prov.Env("mergeRequest.sourceHash", env, "APPVEYOR_PULL_REQUEST_HEAD_COMMIT")
+		nci.MergeRequest.TargetBranchName = prov.Env("mergeRequest.targetBranchName", env, "APPVEYOR_REPO_BRANCH")
+	}
 
 	// repository
 	projectDir, err := vcsutil.FindProjectDirectoryFromWorkDir()
 	if err != nil {
-		return nci, fmt.Errorf("failed to find project directory: %v", err)
+		return nci, prov, fmt.Errorf("failed to find project directory: %v", err)
 	}
 	vcsData, err := vcsrepository.GetVCSRepositoryInformation(projectDir)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get repository details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get repository details: %v", err)
 	}
 	nci.Repository = vcsData.Repository
 	nci.Commit = vcsData.Commit
-	nci.Commit.Hash = env["APPVEYOR_REPO_COMMIT"]
-	nci.Commit.HashShort = env["APPVEYOR_REPO_COMMIT"][:7]
+	prov.SetSection(nci, "repository", v1.SourceGit, "")
+	prov.SetSection(nci, "commit", v1.SourceGit, "HEAD")
+	nci.Commit.Hash = prov.Env("commit.hash", env, "APPVEYOR_REPO_COMMIT")
+	prov.Set("commit.hashShort", v1.SourceEnv, "APPVEYOR_REPO_COMMIT")
+	nci.Commit.HashShort = nci.Commit.Hash
+	if len(nci.Commit.Hash) > 7 {
+		nci.Commit.HashShort = nci.Commit.Hash[:7]
+	}
 
 	// project
 	projectData, err := projectdetails.GetProjectDetails(nci.Repository.Kind, nci.Repository.Remote, nci.Repository.HostType, nci.Repository.HostServer)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get project details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get project details: %v", err)
 	}
 	nci.Project = projectData
+	prov.SetSection(nci, "project", v1.SourceAPI, nci.Repository.HostType)
 	nci.Project.Dir = projectDir
+	prov.Set("project.dir", v1.SourceGit, "")
 
 	// flags
 	nci.Flags.DeployFreeze = "false"
 
-	return nci, nil
+	prov.Complete(nci)
+	return nci, prov, nil
+}
+
+// appveyorTriggerNormalize derives the trigger from the build flags, AppVeyor has no trigger for builds started by other builds
+func appveyorTriggerNormalize(env map[string]string) string {
+	if env["APPVEYOR_PULL_REQUEST_NUMBER"] != "" {
+		return common.PipelineTriggerMergeRequest
+	}
+	if strings.EqualFold(env["APPVEYOR_SCHEDULED_BUILD"], "true") {
+		return common.PipelineTriggerSchedule
+	}
+	if strings.EqualFold(env["APPVEYOR_FORCED_BUILD"], "true") || strings.EqualFold(env["APPVEYOR_RE_BUILD"], "true") {
+		return common.PipelineTriggerManual
+	}
+
+	return common.PipelineTriggerPush
 }
@@ -99,6 +99,46 @@ func TestNormalizer_Normalize_Project(t *testing.T) {
 }
 
 func TestNormalizer_Normalize_PullRequest(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	var normalizer = NewNormalizer()
+	var normalized, err = normalizer.Normalize(map[string]string{
+		"APPVEYOR_REPO_BRANCH":                   "main",
+		"APPVEYOR_PULL_REQUEST_NUMBER":           "42",
+		"APPVEYOR_PULL_REQUEST_TITLE":            "feat: add appveyor",
+		"APPVEYOR_PULL_REQUEST_HEAD_REPO_BRANCH": "feat/appveyor",
+		"APPVEYOR_PULL_REQUEST_HEAD_COMMIT":      "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "merge_request", normalized.Pipeline.Trigger)
+	assert.Equal(t, "42", normalized.MergeRequest.Id)
+	assert.Equal(t, "feat: add appveyor", normalized.MergeRequest.Title)
+	assert.Equal(t, "feat/appveyor", normalized.MergeRequest.SourceBranchName)
+	assert.Equal(t, "790efd9b96e59d9b3c3f1899284c85fa91efbcbc", normalized.MergeRequest.SourceHash)
+	assert.Equal(t, "main", normalized.MergeRequest.TargetBranchName)
+}
+
+func TestNormalizer_Normalize_Trigger(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	tests := []struct {
+		env     map[string]string
+		trigger string
+	}{
+		{map[string]string{}, "push"},
+		{map[string]string{"APPVEYOR_SCHEDULED_BUILD": "True"}, "schedule"},
+		{map[string]string{"APPVEYOR_FORCED_BUILD": "True"}, "manual"},
+		{map[string]string{"APPVEYOR_RE_BUILD": "True"}, "manual"},
+		{map[string]string{"APPVEYOR_PULL_REQUEST_NUMBER": "42"}, "merge_request"},
+	}
+	for _, test := range tests {
+		var normalizer = NewNormalizer()
+		var normalized, err = normalizer.Normalize(test.env)
+
+		assert.NoError(t, err, test.trigger)
+		assert.Equal(t, test.trigger, normalized.Pipeline.Trigger)
+	}
 }
 
 func TestNormalizer_Normalize_WorkflowAPI(t *testing.T) {
@@ -0,0 +1,58 @@
+package appveyor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/rs/zerolog/log"
+)
+
+var appveyorMockClient *http.Client
+
+// Propagate sets the variables for the rest of the build using the build agent API, see https://www.appveyor.com/docs/build-worker-api/#add-environment-variable
+// AppVeyor can't mask secrets at runtime.
+func (n Normalizer) Propagate(env map[string]string, vars map[string]string, opts api.PropagateOptions) (string, error) {
+	apiURL := env["APPVEYOR_API_URL"]
+	if apiURL == "" {
+		return "", errors.New("APPVEYOR_API_URL is not set")
+	}
+
+	keys, err := nciutil.SortedEnvKeys(vars)
+	if err != nil {
+		return "", err
+	}
+
+	client := http.DefaultClient
+	if appveyorMockClient != nil {
+		client = appveyorMockClient
+	}
+
+	endpoint := strings.TrimSuffix(apiURL, "/") + "/api/build/variables"
+	for _, key := range keys {
+		if opts.IsSecret(key) {
+			log.Warn().Str("key", key).Msg("AppVeyor can't mask secrets at runtime, the value may show up in the build log")
+		}
+
+		body, err := json.Marshal(map[string]string{"name": key, "value": vars[key]})
+		if err != nil {
+			return "", fmt.Errorf("failed to encode variable %s: %w", key, err)
+		}
+
+		resp, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return "", fmt.Errorf("failed to set variable %s: %w", key, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return "", fmt.Errorf("failed to set variable %s: %s", key, resp.Status)
+		}
+	}
+
+	return "", nil
+}
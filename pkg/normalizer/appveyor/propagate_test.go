@@ -0,0 +1,41 @@
+package appveyor
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizer_Propagate(t *testing.T) {
+	appveyorMockClient = &http.Client{}
+	httpmock.ActivateNonDefault(appveyorMockClient)
+	defer func() {
+		httpmock.DeactivateAndReset()
+		appveyorMockClient = nil
+	}()
+
+	var received []string
+	httpmock.RegisterResponder("POST", "http://localhost:1028/api/build/variables", func(req *http.Request) (*http.Response, error) {
+		var body map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		received = append(received, body["name"]+"="+body["value"])
+		return httpmock.NewStringResponse(204, ""), nil
+	})
+
+	var normalizer = NewNormalizer()
+	output, err := normalizer.Propagate(
+		map[string]string{"APPVEYOR_API_URL": "http://localhost:1028/"},
+		map[string]string{"NCI_B": "line 1\nline 2", "NCI_A": "a"},
+		api.PropagateOptions{},
+	)
+
+	assert.NoError(t, err)
+	assert.Empty(t, output)
+	assert.Equal(t, []string{"NCI_A=a", "NCI_B=line 1\nline 2"}, received)
+}
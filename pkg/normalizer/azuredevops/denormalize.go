@@ -1,9 +1,125 @@
 package azuredevops
 
 import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
 	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
 )
 
+// pipelineURLRegex matches the pipeline url generated by Normalize (<server>/<project>/_build/results?buildId=<id>)
+var pipelineURLRegex = regexp.MustCompile(`^(.+/)([^/]+)/_build/results\?buildId=([^&]+)$`)
+
+// Denormalize generates the Azure DevOps predefined variables from the common format
 func (n Normalizer) Denormalize(spec v1.Spec) (map[string]string, error) {
-	return make(map[string]string), nil
+	data := make(map[string]string)
+
+	// common
+	data["TF_BUILD"] = "True"
+
+	// worker
+	agentOS, agentArch := nciutil.SplitArch(spec.Worker.Arch)
+	data["AGENT_ID"] = spec.Worker.Id
+	data["AGENT_NAME"] = spec.Worker.Name
+	data["AGENT_MACHINENAME"] = spec.Worker.Name
+	data["AGENT_VERSION"] = spec.Worker.Version
+	data["AGENT_OS"] = azureAgentOS(agentOS)
+	data["AGENT_OSARCHITECTURE"] = nciutil.ToRunnerArch(agentArch)
+	imageParts := strings.SplitN(spec.Worker.OS, ":", 2)
+	data["ImageOS"] = imageParts[0]
+	if len(imageParts) == 2 {
+		data["ImageVersion"] = imageParts[1]
+	}
+
+	// pipeline
+	data["SYSTEM_PHASEID"] = spec.Pipeline.Id
+	data["BUILD_REASON"] = azureBuildReason(spec.Pipeline.Trigger)
+	data["SYSTEM_STAGEID"] = spec.Pipeline.StageId
+	data["SYSTEM_STAGENAME"] = spec.Pipeline.StageName
+	data["SYSTEM_STAGEDISPLAYNAME"] = spec.Pipeline.StageName
+	data["SYSTEM_JOBID"] = spec.Pipeline.JobId
+	data["SYSTEM_JOBNAME"] = spec.Pipeline.JobName
+	data["SYSTEM_JOBDISPLAYNAME"] = spec.Pipeline.JobName
+	data["SYSTEM_JOBATTEMPT"] = spec.Pipeline.Attempt
+	if match := pipelineURLRegex.FindStringSubmatch(spec.Pipeline.Url); match != nil {
+		data["SYSTEM_TEAMFOUNDATIONSERVERURI"] = match[1]
+		data["SYSTEM_COLLECTIONURI"] = match[1]
+		data["SYSTEM_TEAMPROJECT"] = match[2]
+		data["BUILD_BUILDID"] = match[3]
+	}
+
+	// repository and project
+	data["BUILD_REPOSITORY_ID"] = spec.Project.Id
+	data["BUILD_REPOSITORY_NAME"] = spec.Project.Path
+	data["BUILD_REPOSITORY_URI"] = spec.Project.Url
+	data["BUILD_REPOSITORY_PROVIDER"] = azureRepositoryProvider(spec.Repository.HostType)
+	data["BUILD_SOURCESDIRECTORY"] = spec.Project.Dir
+	data["BUILD_REPOSITORY_LOCALPATH"] = spec.Project.Dir
+	data["SYSTEM_DEFAULTWORKINGDIRECTORY"] = spec.Project.Dir
+
+	// commit
+	data["BUILD_SOURCEVERSION"] = spec.Commit.Hash
+	data["BUILD_SOURCEBRANCH"] = spec.Commit.RefVCS
+	data["BUILD_SOURCEBRANCHNAME"] = path.Base(spec.Commit.RefName)
+	data["BUILD_SOURCEVERSIONMESSAGE"] = spec.Commit.Title
+	data["BUILD_SOURCEVERSIONAUTHOR"] = spec.Commit.AuthorName
+	data["BUILD_REQUESTEDFOR"] = spec.Commit.AuthorName
+	data["BUILD_REQUESTEDFOREMAIL"] = spec.Commit.AuthorEmail
+
+	// pull request
+	if spec.MergeRequest.Id != "" {
+		data["SYSTEM_PULLREQUEST_PULLREQUESTID"] = spec.MergeRequest.Id
+		data["SYSTEM_PULLREQUEST_PULLREQUESTNUMBER"] = spec.MergeRequest.Id
+		data["SYSTEM_PULLREQUEST_SOURCEBRANCH"] = "refs/heads/" + spec.MergeRequest.SourceBranchName
+		data["SYSTEM_PULLREQUEST_SOURCECOMMITID"] = spec.MergeRequest.SourceHash
+		data["SYSTEM_PULLREQUEST_TARGETBRANCH"] = "refs/heads/" + spec.MergeRequest.TargetBranchName
+		data["SYSTEM_PULLREQUEST_TARGETBRANCHNAME"] = spec.MergeRequest.TargetBranchName
+		data["SYSTEM_PULLREQUEST_SOURCEREPOSITORYURI"] = spec.Project.Url
+	}
+
+	return data, nil
+}
+
+func azureBuildReason(trigger string) string {
+	switch trigger {
+	case common.PipelineTriggerPush:
+		return "IndividualCI"
+	case common.PipelineTriggerSchedule:
+		return "Schedule"
+	case common.PipelineTriggerMergeRequest:
+		return "PullRequest"
+	case common.PipelineTriggerBuild:
+		return "BuildCompletion"
+	default:
+		return "Manual"
+	}
+}
+
+func azureAgentOS(goos string) string {
+	switch goos {
+	case "linux":
+		return "Linux"
+	case "windows":
+		return "Windows_NT"
+	case "darwin":
+		return "Darwin"
+	default:
+		return goos
+	}
+}
+
+func azureRepositoryProvider(hostType string) string {
+	switch hostType {
+	case "github":
+		return "GitHub"
+	case "bitbucket":
+		return "Bitbucket"
+	case "azuredevops":
+		return "TfsGit"
+	default:
+		return "Git"
+	}
 }
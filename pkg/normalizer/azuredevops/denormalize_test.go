@@ -0,0 +1,105 @@
+package azuredevops
+
+import (
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/normalizertest"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSpec = v1.Spec{
+	Worker: v1.Worker{
+		Id:      "9",
+		Name:    "fv-az158-714",
+		OS:      "ubuntu20:20220503.1",
+		Version: "2.202.1",
+		Arch:    "linux/amd64",
+	},
+	Pipeline: v1.Pipeline{
+		Id:        "a11efe29-9b58-5a6c-3fa4-3e36996dcbd8",
+		Trigger:   common.PipelineTriggerPush,
+		StageId:   "6884a131-87da-5381-61f3-d7acc3b91d76",
+		StageName: "build",
+		StageSlug: "build",
+		JobId:     "3dc8fd7e-4368-5a92-293e-d53cefc8c4b3",
+		JobName:   "compile",
+		JobSlug:   "compile",
+		Attempt:   "3",
+		Url:       "https://heuer.visualstudio.com/cienvsamples/_build/results?buildId=11",
+	},
+	Repository: v1.Repository{
+		HostType: "github",
+	},
+	Project: v1.Project{
+		Id:   "cidverse/cienvsamples",
+		Path: "cidverse/cienvsamples",
+		Url:  "https://github.com/cidverse/cienvsamples",
+		Dir:  "/home/vsts/work/1/s",
+	},
+	Commit: v1.Commit{
+		RefType: "branch",
+		RefName: "feature/denormalize",
+		RefVCS:  "refs/heads/feature/denormalize",
+		Hash:    "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+		Title:   "feat: add denormalize",
+	},
+}
+
+func TestNormalizer_Denormalize(t *testing.T) {
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(testSpec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "True", denormalized["TF_BUILD"])
+	assert.Equal(t, "Linux", denormalized["AGENT_OS"])
+	assert.Equal(t, "X64", denormalized["AGENT_OSARCHITECTURE"])
+	assert.Equal(t, "IndividualCI", denormalized["BUILD_REASON"])
+	assert.Equal(t, "https://heuer.visualstudio.com/", denormalized["SYSTEM_TEAMFOUNDATIONSERVERURI"])
+	assert.Equal(t, "cienvsamples", denormalized["SYSTEM_TEAMPROJECT"])
+	assert.Equal(t, "11", denormalized["BUILD_BUILDID"])
+	assert.Equal(t, "GitHub", denormalized["BUILD_REPOSITORY_PROVIDER"])
+	assert.Equal(t, "790efd9b96e59d9b3c3f1899284c85fa91efbcbc", denormalized["BUILD_SOURCEVERSION"])
+	assert.Equal(t, "refs/heads/feature/denormalize", denormalized["BUILD_SOURCEBRANCH"])
+	assert.Equal(t, "denormalize", denormalized["BUILD_SOURCEBRANCHNAME"])
+	assert.NotContains(t, denormalized, "SYSTEM_PULLREQUEST_PULLREQUESTID")
+}
+
+func TestNormalizer_Denormalize_PullRequest(t *testing.T) {
+	spec := testSpec
+	spec.Pipeline.Trigger = common.PipelineTriggerMergeRequest
+	spec.MergeRequest = v1.MergeRequest{Id: "7", SourceBranchName: "feature/denormalize", TargetBranchName: "main"}
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PullRequest", denormalized["BUILD_REASON"])
+	assert.Equal(t, "7", denormalized["SYSTEM_PULLREQUEST_PULLREQUESTID"])
+	assert.Equal(t, "refs/heads/feature/denormalize", denormalized["SYSTEM_PULLREQUEST_SOURCEBRANCH"])
+	assert.Equal(t, "refs/heads/main", denormalized["SYSTEM_PULLREQUEST_TARGETBRANCH"])
+}
+
+func TestNormalizer_Denormalize_RoundTrip(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	normalizertest.AssertRoundTrip(t, NewNormalizer(), testSpec, []string{
+		"NCI_WORKER_ID",
+		"NCI_WORKER_NAME",
+		"NCI_WORKER_OS",
+		"NCI_WORKER_VERSION",
+		"NCI_PIPELINE_ID",
+		"NCI_PIPELINE_TRIGGER",
+		"NCI_PIPELINE_STAGE_ID",
+		"NCI_PIPELINE_STAGE_NAME",
+		"NCI_PIPELINE_STAGE_SLUG",
+		"NCI_PIPELINE_JOB_ID",
+		"NCI_PIPELINE_JOB_NAME",
+		"NCI_PIPELINE_JOB_SLUG",
+		"NCI_PIPELINE_ATTEMPT",
+		"NCI_PIPELINE_URL",
+		"NCI_PROJECT_URL",
+	})
+}
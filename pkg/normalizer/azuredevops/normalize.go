@@ -3,6 +3,7 @@ package azuredevops
 import (
 	"fmt"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/cidverse/go-vcs/vcsutil"
@@ -15,66 +16,94 @@ import (
 
 // Normalize normalizes the environment variables into the common format
 func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
+	nci, _, err := n.NormalizeWithProvenance(env)
+	return nci, err
+}
+
+// NormalizeWithProvenance normalizes the environment variables into the common format and records the source of each field
+func (n Normalizer) NormalizeWithProvenance(env map[string]string) (v1.Spec, v1.Provenance, error) {
 	nci := v1.Create(n.name, n.slug)
+	prov := v1.Provenance{}
 
 	// worker
 	nci.Worker = v1.Worker{
-		Id:      env["AGENT_ID"],
-		Name:    env["AGENT_MACHINENAME"],
+		Id:      prov.Env("worker.id", env, "AGENT_ID"),
+		Name:    prov.Env("worker.name", env, "AGENT_MACHINENAME"),
 		Type:    "azuredevops_hosted_vm",
 		OS:      env["ImageOS"] + ":" + env["ImageVersion"],
-		Version: env["AGENT_VERSION"],
+		Version: prov.Env("worker.version", env, "AGENT_VERSION"),
 		Arch:    runtime.GOOS + "/" + runtime.GOARCH,
 	}
 
+	prov.Set("worker.os", v1.SourceEnv, "ImageOS, ImageVersion")
+
 	// pipeline
-	nci.Pipeline.Id = env["SYSTEM_PHASEID"]
-	if env["BUILD_REASON"] == "Manual" {
-		nci.Pipeline.Trigger = common.PipelineTriggerManual
-	} else if env["BUILD_REASON"] == "IndividualCI" || env["BUILD_REASON"] == "BatchedCI" {
-		nci.Pipeline.Trigger = common.PipelineTriggerPush
-	} else if env["BUILD_REASON"] == "Schedule" {
-		nci.Pipeline.Trigger = common.PipelineTriggerSchedule
-	} else if env["BUILD_REASON"] == "PullRequest" {
-		nci.Pipeline.Trigger = common.PipelineTriggerMergeRequest
-	} else if env["BUILD_REASON"] == "BuildCompletion" {
-		nci.Pipeline.Trigger = common.PipelineTriggerBuild
-	} else {
-		nci.Pipeline.Trigger = common.PipelineTriggerUnknown
-	}
-	nci.Pipeline.StageId = env["SYSTEM_STAGEID"]
-	nci.Pipeline.StageName = env["SYSTEM_STAGENAME"] // SYSTEM_STAGEDISPLAYNAME
-	nci.Pipeline.StageSlug = slug.Make(env["SYSTEM_STAGENAME"])
-	nci.Pipeline.JobId = env["SYSTEM_JOBID"]
-	nci.Pipeline.JobName = env["SYSTEM_JOBNAME"] // SYSTEM_JOBDISPLAYNAME
-	nci.Pipeline.JobSlug = slug.Make(env["SYSTEM_JOBNAME"])
+	nci.Pipeline.Id = prov.Env("pipeline.id", env, "SYSTEM_PHASEID")
+	prov.Set("pipeline.trigger", v1.SourceEnv, "BUILD_REASON")
+	nci.Pipeline.Trigger = azureTriggerNormalize(env["BUILD_REASON"])
+	nci.Pipeline.StageId = prov.Env("pipeline.stageId", env, "SYSTEM_STAGEID")
+	nci.Pipeline.StageName = prov.Env("pipeline.stageName", env, "SYSTEM_STAGENAME") // SYSTEM_STAGEDISPLAYNAME
+	nci.Pipeline.StageSlug = slug.Make(prov.Env("pipeline.stageSlug", env, "SYSTEM_STAGENAME"))
+	nci.Pipeline.JobId = prov.Env("pipeline.jobId", env, "SYSTEM_JOBID")
+	nci.Pipeline.JobName = prov.Env("pipeline.jobName", env, "SYSTEM_JOBNAME") // SYSTEM_JOBDISPLAYNAME
+	nci.Pipeline.JobSlug = slug.Make(prov.Env("pipeline.jobSlug", env, "SYSTEM_JOBNAME"))
 	nci.Pipeline.JobStartedAt = time.Now().Format(time.RFC3339)
-	nci.Pipeline.Attempt = env["SYSTEM_JOBATTEMPT"]
+	nci.Pipeline.Attempt = prov.Env("pipeline.attempt", env, "SYSTEM_JOBATTEMPT")
 	nci.Pipeline.Url = fmt.Sprintf("%s%s/_build/results?buildId=%s", env["SYSTEM_TEAMFOUNDATIONSERVERURI"], env["SYSTEM_TEAMPROJECT"], env["BUILD_BUILDID"])
+	prov.Set("pipeline.url", v1.SourceEnv, "SYSTEM_TEAMFOUNDATIONSERVERURI, SYSTEM_TEAMPROJECT, BUILD_BUILDID")
+
+	// pull request, the id is internal for GitHub pull requests and the number is only set for those
+	if _, isPullRequest := env["SYSTEM_PULLREQUEST_PULLREQUESTID"]; isPullRequest {
+		nci.MergeRequest.Id = prov.First("mergeRequest.id", v1.EnvCandidate(env, "SYSTEM_PULLREQUEST_PULLREQUESTNUMBER"), v1.EnvCandidate(env, "SYSTEM_PULLREQUEST_PULLREQUESTID"))
+		nci.MergeRequest.SourceBranchName = strings.TrimPrefix(prov.Env("mergeRequest.sourceBranchName", env, "SYSTEM_PULLREQUEST_SOURCEBRANCH"), "refs/heads/")
+		nci.MergeRequest.SourceHash = prov.Env("mergeRequest.sourceHash", env, "SYSTEM_PULLREQUEST_SOURCECOMMITID")
+		nci.MergeRequest.TargetBranchName = strings.TrimPrefix(prov.Env("mergeRequest.targetBranchName", env, "SYSTEM_PULLREQUEST_TARGETBRANCH"), "refs/heads/")
+	}
 
 	// repository
 	projectDir, err := vcsutil.FindProjectDirectoryFromWorkDir()
 	if err != nil {
-		return nci, fmt.Errorf("failed to find project directory: %v", err)
+		return nci, prov, fmt.Errorf("failed to find project directory: %v", err)
 	}
 	vcsData, err := vcsrepository.GetVCSRepositoryInformation(projectDir)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get repository details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get repository details: %v", err)
 	}
 	nci.Repository = vcsData.Repository
 	nci.Commit = vcsData.Commit
+	prov.SetSection(nci, "repository", v1.SourceGit, "")
+	prov.SetSection(nci, "commit", v1.SourceGit, "HEAD")
 
 	// project
 	projectData, err := projectdetails.GetProjectDetails(nci.Repository.Kind, nci.Repository.Remote, nci.Repository.HostType, nci.Repository.HostServer)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get project details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get project details: %v", err)
 	}
 	nci.Project = projectData
-	nci.Project.Url = env["BUILD_REPOSITORY_URI"]
+	prov.SetSection(nci, "project", v1.SourceAPI, nci.Repository.HostType)
+	nci.Project.Url = prov.Env("project.url", env, "BUILD_REPOSITORY_URI")
 	nci.Project.Dir = projectDir
+	prov.Set("project.dir", v1.SourceGit, "")
 
 	// flags
 	nci.Flags.DeployFreeze = "false"
 
-	return nci, nil
+	prov.Complete(nci)
+	return nci, prov, nil
+}
+
+func azureTriggerNormalize(reason string) string {
+	if reason == "Manual" {
+		return common.PipelineTriggerManual
+	} else if reason == "IndividualCI" || reason == "BatchedCI" {
+		return common.PipelineTriggerPush
+	} else if reason == "Schedule" {
+		return common.PipelineTriggerSchedule
+	} else if reason == "PullRequest" {
+		return common.PipelineTriggerMergeRequest
+	} else if reason == "BuildCompletion" {
+		return common.PipelineTriggerBuild
+	}
+
+	return common.PipelineTriggerUnknown
 }
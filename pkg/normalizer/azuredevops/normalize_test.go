@@ -77,6 +77,24 @@ func TestNormalizer_Normalize_Project(t *testing.T) {
 }
 
 func TestNormalizer_Normalize_PullRequest(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	var normalizer = NewNormalizer()
+	var normalized, err = normalizer.Normalize(map[string]string{
+		"BUILD_REASON":                         "PullRequest",
+		"SYSTEM_PULLREQUEST_PULLREQUESTID":     "1234567890",
+		"SYSTEM_PULLREQUEST_PULLREQUESTNUMBER": "42",
+		"SYSTEM_PULLREQUEST_SOURCEBRANCH":      "refs/heads/feat/azure",
+		"SYSTEM_PULLREQUEST_SOURCECOMMITID":    "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+		"SYSTEM_PULLREQUEST_TARGETBRANCH":      "refs/heads/main",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "merge_request", normalized.Pipeline.Trigger)
+	assert.Equal(t, "42", normalized.MergeRequest.Id)
+	assert.Equal(t, "feat/azure", normalized.MergeRequest.SourceBranchName)
+	assert.Equal(t, "790efd9b96e59d9b3c3f1899284c85fa91efbcbc", normalized.MergeRequest.SourceHash)
+	assert.Equal(t, "main", normalized.MergeRequest.TargetBranchName)
 }
 
 func TestNormalizer_Normalize_WorkflowAPI(t *testing.T) {
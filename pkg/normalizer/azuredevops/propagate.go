@@ -0,0 +1,36 @@
+package azuredevops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+)
+
+// commandEscaper escapes the data of a logging command, see https://learn.microsoft.com/en-us/azure/devops/pipelines/scripts/logging-commands
+var commandEscaper = strings.NewReplacer(
+	"%", "%AZP25",
+	"\r", "%0D",
+	"\n", "%0A",
+)
+
+// Propagate sets the variables for subsequent steps using task.setvariable logging commands.
+// Secret variables are masked, but Azure DevOps only passes them to steps that map them explicitly.
+func (n Normalizer) Propagate(env map[string]string, vars map[string]string, opts api.PropagateOptions) (string, error) {
+	keys, err := nciutil.SortedEnvKeys(vars)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	for _, key := range keys {
+		properties := "variable=" + key
+		if opts.IsSecret(key) {
+			properties += ";issecret=true"
+		}
+		output.WriteString(fmt.Sprintf("##vso[task.setvariable %s]%s\n", properties, commandEscaper.Replace(vars[key])))
+	}
+
+	return output.String(), nil
+}
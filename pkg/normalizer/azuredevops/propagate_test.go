@@ -0,0 +1,23 @@
+package azuredevops
+
+import (
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizer_Propagate(t *testing.T) {
+	var normalizer = NewNormalizer()
+	output, err := normalizer.Propagate(
+		map[string]string{},
+		map[string]string{
+			"NCI_COMMIT_DESCRIPTION": "100% done\r\n##vso[task.complete result=Failed]",
+			"NCI_TOKEN":              "secret",
+		},
+		api.PropagateOptions{Secrets: []string{"NCI_TOKEN"}},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "##vso[task.setvariable variable=NCI_COMMIT_DESCRIPTION]100%AZP25 done%0D%0A##vso[task.complete result=Failed]\n##vso[task.setvariable variable=NCI_TOKEN;issecret=true]secret\n", output)
+}
@@ -0,0 +1,26 @@
+package azuredevops
+
+import (
+	"fmt"
+	"os"
+)
+
+// WriteSummary stores the markdown in the agent temp directory and attaches it to the build summary using the task.uploadsummary logging command
+func (n Normalizer) WriteSummary(env map[string]string, markdown string) (string, error) {
+	dir := env["AGENT_TEMPDIRECTORY"]
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	file, err := os.CreateTemp(dir, "nci-summary-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create summary file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = file.WriteString(markdown); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", file.Name(), err)
+	}
+
+	return "##vso[task.uploadsummary]" + commandEscaper.Replace(file.Name()) + "\n", nil
+}
@@ -0,0 +1,21 @@
+package azuredevops
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizer_WriteSummary(t *testing.T) {
+	dir := t.TempDir()
+	var normalizer = NewNormalizer()
+	output, err := normalizer.WriteSummary(map[string]string{"AGENT_TEMPDIRECTORY": dir}, "### Build context\n")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(output, "##vso[task.uploadsummary]"+dir))
+
+	content, err := os.ReadFile(strings.TrimSuffix(strings.TrimPrefix(output, "##vso[task.uploadsummary]"), "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "### Build context\n", string(content))
+}
@@ -1,5 +1,9 @@
 package circleci
 
+import (
+	"strings"
+)
+
 // Normalizer is the implementation of the normalizer
 type Normalizer struct {
 	version string
@@ -17,9 +21,24 @@ func (n Normalizer) GetSlug() string {
 	return n.slug
 }
 
+// GetVersion returns the version of the normalizer
+func (n Normalizer) GetVersion() string {
+	return n.version
+}
+
+// GetCheckVariables returns the names of the environment variables evaluated by Check
+func (n Normalizer) GetCheckVariables() []string {
+	return []string{"CIRCLECI"}
+}
+
+// SupportsDenormalize returns true if the normalizer can generate the native environment from the common format
+func (n Normalizer) SupportsDenormalize() bool {
+	return true
+}
+
 // Check if this package can handle the current environment
 func (n Normalizer) Check(env map[string]string) bool {
-	return env["CIRCLECI"] == "True"
+	return strings.EqualFold(env["CIRCLECI"], "true")
 }
 
 // NewNormalizer gets a instance of the normalizer
@@ -1,9 +1,64 @@
 package circleci
 
 import (
+	"strings"
+
 	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
 )
 
+// Denormalize generates the CircleCI built-in environment variables from the common format
 func (n Normalizer) Denormalize(spec v1.Spec) (map[string]string, error) {
-	return make(map[string]string), nil
+	data := make(map[string]string)
+
+	// common
+	data["CI"] = "true"
+	data["CIRCLECI"] = "true"
+
+	// worker
+	data["CIRCLE_NODE_INDEX"] = spec.Worker.Id
+	data["CIRCLE_NODE_TOTAL"] = "1"
+
+	// pipeline
+	data["CIRCLE_PIPELINE_ID"] = spec.Pipeline.Id
+	data["CIRCLE_WORKFLOW_ID"] = spec.Pipeline.StageId
+	data["CIRCLE_WORKFLOW_JOB_ID"] = spec.Pipeline.JobId
+	data["CIRCLE_JOB"] = spec.Pipeline.JobName
+	data["CIRCLE_BUILD_URL"] = spec.Pipeline.Url
+
+	// repository and project
+	pathParts := strings.SplitN(spec.Project.Path, "/", 2)
+	data["CIRCLE_PROJECT_USERNAME"] = pathParts[0]
+	if len(pathParts) == 2 {
+		data["CIRCLE_PROJECT_REPONAME"] = pathParts[1]
+	}
+	data["CIRCLE_REPOSITORY_URL"] = spec.Repository.Remote
+	data["CIRCLE_WORKING_DIRECTORY"] = spec.Project.Dir
+
+	// commit
+	data["CIRCLE_SHA1"] = spec.Commit.Hash
+	if spec.Commit.RefType == "tag" {
+		data["CIRCLE_TAG"] = spec.Commit.RefName
+	} else {
+		data["CIRCLE_BRANCH"] = spec.Commit.RefName
+	}
+	data["CIRCLE_USERNAME"] = spec.Commit.AuthorName
+
+	// pull request
+	if spec.MergeRequest.Id != "" {
+		pullRequestURL := circlePullRequestURL(spec)
+		data["CIRCLE_PR_NUMBER"] = spec.MergeRequest.Id
+		data["CIRCLE_PULL_REQUEST"] = pullRequestURL
+		data["CIRCLE_PULL_REQUESTS"] = pullRequestURL
+	}
+
+	return data, nil
+}
+
+// circlePullRequestURL returns the web url of the merge request on the repository host
+func circlePullRequestURL(spec v1.Spec) string {
+	if spec.Repository.HostType == "gitlab" {
+		return spec.Project.Url + "/-/merge_requests/" + spec.MergeRequest.Id
+	}
+
+	return spec.Project.Url + "/pull/" + spec.MergeRequest.Id
 }
@@ -0,0 +1,83 @@
+package circleci
+
+import (
+	"testing"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/normalizertest"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSpec = v1.Spec{
+	Worker: v1.Worker{
+		Id: "0",
+	},
+	Pipeline: v1.Pipeline{
+		Id:        "ec202ec0-b88f-47cf-9df0-f85979ea3426",
+		StageId:   "00adcc65-3be1-463b-b5f6-f2102329bb00",
+		StageName: "default",
+		StageSlug: "default",
+		JobId:     "7564f453-074e-43cf-aef3-a393a9909474",
+		JobName:   "publish-env",
+		JobSlug:   "publish-env",
+		Url:       "https://app.circleci.com/jobs/circleci/0d12c8fa-cf92-415b-a14c-4787b20c52e2/aa4638b8-064e-4321-b1ee-794f3028b01f/9",
+	},
+	Repository: v1.Repository{
+		Remote:   "https://github.com/cidverse/cienvsamples.git",
+		HostType: "github",
+	},
+	Project: v1.Project{
+		Path: "cidverse/cienvsamples",
+		Url:  "https://github.com/cidverse/cienvsamples",
+		Dir:  "/root/project",
+	},
+	Commit: v1.Commit{
+		RefType: "branch",
+		RefName: "main",
+		Hash:    "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+	},
+}
+
+func TestNormalizer_Denormalize(t *testing.T) {
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(testSpec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "true", denormalized["CIRCLECI"])
+	assert.Equal(t, "cidverse", denormalized["CIRCLE_PROJECT_USERNAME"])
+	assert.Equal(t, "cienvsamples", denormalized["CIRCLE_PROJECT_REPONAME"])
+	assert.Equal(t, "https://github.com/cidverse/cienvsamples.git", denormalized["CIRCLE_REPOSITORY_URL"])
+	assert.Equal(t, "790efd9b96e59d9b3c3f1899284c85fa91efbcbc", denormalized["CIRCLE_SHA1"])
+	assert.Equal(t, "main", denormalized["CIRCLE_BRANCH"])
+	assert.NotContains(t, denormalized, "CIRCLE_TAG")
+	assert.NotContains(t, denormalized, "CIRCLE_PR_NUMBER")
+}
+
+func TestNormalizer_Denormalize_PullRequest(t *testing.T) {
+	spec := testSpec
+	spec.MergeRequest = v1.MergeRequest{Id: "12"}
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12", denormalized["CIRCLE_PR_NUMBER"])
+	assert.Equal(t, "https://github.com/cidverse/cienvsamples/pull/12", denormalized["CIRCLE_PULL_REQUEST"])
+}
+
+func TestNormalizer_Denormalize_RoundTrip(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	normalizertest.AssertRoundTrip(t, NewNormalizer(), testSpec, []string{
+		"NCI_WORKER_ID",
+		"NCI_PIPELINE_ID",
+		"NCI_PIPELINE_STAGE_ID",
+		"NCI_PIPELINE_STAGE_NAME",
+		"NCI_PIPELINE_STAGE_SLUG",
+		"NCI_PIPELINE_JOB_ID",
+		"NCI_PIPELINE_JOB_NAME",
+		"NCI_PIPELINE_JOB_SLUG",
+		"NCI_PIPELINE_URL",
+	})
+}
@@ -2,6 +2,7 @@ package circleci
 
 import (
 	"fmt"
+	"path"
 	"runtime"
 	"time"
 
@@ -15,12 +16,19 @@ import (
 
 // Normalize normalizes the environment variables into the common format
 func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
+	nci, _, err := n.NormalizeWithProvenance(env)
+	return nci, err
+}
+
+// NormalizeWithProvenance normalizes the environment variables into the common format and records the source of each field
+func (n Normalizer) NormalizeWithProvenance(env map[string]string) (v1.Spec, v1.Provenance, error) {
 	nci := v1.Create(n.name, n.slug)
+	prov := v1.Provenance{}
 
 	// worker
 	nci.Worker = v1.Worker{
-		Id:      env["CIRCLE_NODE_INDEX"],
-		Name:    env["CIRCLE_NODE_INDEX"],
+		Id:      prov.Env("worker.id", env, "CIRCLE_NODE_INDEX"),
+		Name:    prov.Env("worker.name", env, "CIRCLE_NODE_INDEX"),
 		Type:    "circleci_hosted_vm",
 		OS:      "unknown",
 		Version: "latest",
@@ -28,40 +36,53 @@ func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
 	}
 
 	// pipeline
-	nci.Pipeline.Id = env["CIRCLE_PIPELINE_ID"]
+	nci.Pipeline.Id = prov.Env("pipeline.id", env, "CIRCLE_PIPELINE_ID")
 	nci.Pipeline.Trigger = common.PipelineTriggerUnknown
-	nci.Pipeline.StageId = env["CIRCLE_WORKFLOW_ID"]
+	nci.Pipeline.StageId = prov.Env("pipeline.stageId", env, "CIRCLE_WORKFLOW_ID")
 	nci.Pipeline.StageName = "default"
 	nci.Pipeline.StageSlug = slug.Make("default")
-	nci.Pipeline.JobId = env["CIRCLE_WORKFLOW_JOB_ID"]
-	nci.Pipeline.JobName = env["CIRCLE_JOB"]
-	nci.Pipeline.JobSlug = slug.Make(env["CIRCLE_JOB"])
+	nci.Pipeline.JobId = prov.Env("pipeline.jobId", env, "CIRCLE_WORKFLOW_JOB_ID")
+	nci.Pipeline.JobName = prov.Env("pipeline.jobName", env, "CIRCLE_JOB")
+	nci.Pipeline.JobSlug = slug.Make(prov.Env("pipeline.jobSlug", env, "CIRCLE_JOB"))
 	nci.Pipeline.JobStartedAt = time.Now().Format(time.RFC3339)
 	nci.Pipeline.Attempt = "0"
-	nci.Pipeline.Url = env["CIRCLE_BUILD_URL"]
+	nci.Pipeline.Url = prov.Env("pipeline.url", env, "CIRCLE_BUILD_URL")
+
+	// pull request, CIRCLE_PR_NUMBER is only set for pull requests from forks and the target branch isn't exposed
+	if pullRequestURL := env["CIRCLE_PULL_REQUEST"]; pullRequestURL != "" {
+		nci.Pipeline.Trigger = common.PipelineTriggerMergeRequest
+		prov.Set("pipeline.trigger", v1.SourceEnv, "CIRCLE_PULL_REQUEST")
+		nci.MergeRequest.Id = prov.First("mergeRequest.id", v1.EnvCandidate(env, "CIRCLE_PR_NUMBER"), v1.SourceCandidate(path.Base(pullRequestURL), v1.SourceEnv, "CIRCLE_PULL_REQUEST"))
+		nci.MergeRequest.SourceBranchName = prov.Env("mergeRequest.sourceBranchName", env, "CIRCLE_BRANCH")
+	}
 
 	// repository
 	projectDir, err := vcsutil.FindProjectDirectoryFromWorkDir()
 	if err != nil {
-		return nci, fmt.Errorf("failed to find project directory: %v", err)
+		return nci, prov, fmt.Errorf("failed to find project directory: %v", err)
 	}
 	vcsData, err := vcsrepository.GetVCSRepositoryInformation(projectDir)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get repository details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get repository details: %v", err)
 	}
 	nci.Repository = vcsData.Repository
 	nci.Commit = vcsData.Commit
+	prov.SetSection(nci, "repository", v1.SourceGit, "")
+	prov.SetSection(nci, "commit", v1.SourceGit, "HEAD")
 
 	// project
 	projectData, err := projectdetails.GetProjectDetails(nci.Repository.Kind, nci.Repository.Remote, nci.Repository.HostType, nci.Repository.HostServer)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get project details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get project details: %v", err)
 	}
 	nci.Project = projectData
+	prov.SetSection(nci, "project", v1.SourceAPI, nci.Repository.HostType)
 	nci.Project.Dir = projectDir
+	prov.Set("project.dir", v1.SourceGit, "")
 
 	// flags
 	nci.Flags.DeployFreeze = "false"
 
-	return nci, nil
+	prov.Complete(nci)
+	return nci, prov, nil
 }
@@ -86,6 +86,18 @@ func TestNormalizer_Normalize_Project(t *testing.T) {
 }
 
 func TestNormalizer_Normalize_PullRequest(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	var normalizer = NewNormalizer()
+	var normalized, err = normalizer.Normalize(map[string]string{
+		"CIRCLE_BRANCH":       "feat/circleci",
+		"CIRCLE_PULL_REQUEST": "https://github.com/cidverse/cienvsamples/pull/42",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "merge_request", normalized.Pipeline.Trigger)
+	assert.Equal(t, "42", normalized.MergeRequest.Id)
+	assert.Equal(t, "feat/circleci", normalized.MergeRequest.SourceBranchName)
 }
 
 func TestNormalizer_Normalize_WorkflowAPI(t *testing.T) {
@@ -0,0 +1,35 @@
+package circleci
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/rs/zerolog/log"
+)
+
+// Propagate appends export statements to the BASH_ENV file, which is sourced by every subsequent step of the job.
+// CircleCI only masks project and context variables, secrets can't be masked at runtime.
+func (n Normalizer) Propagate(env map[string]string, vars map[string]string, opts api.PropagateOptions) (string, error) {
+	envFile := env["BASH_ENV"]
+	if envFile == "" {
+		return "", errors.New("BASH_ENV is not set")
+	}
+
+	keys, err := nciutil.SortedEnvKeys(vars)
+	if err != nil {
+		return "", err
+	}
+
+	var content strings.Builder
+	for _, key := range keys {
+		if opts.IsSecret(key) {
+			log.Warn().Str("key", key).Msg("CircleCI can't mask secrets at runtime, the value may show up in the job log")
+		}
+		content.WriteString(fmt.Sprintf("export %s=%s\n", key, nciutil.QuotePosix(vars[key])))
+	}
+
+	return "", nciutil.AppendToFile(envFile, content.String())
+}
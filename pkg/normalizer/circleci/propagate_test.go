@@ -0,0 +1,28 @@
+package circleci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizer_Propagate(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "bash_env")
+	assert.NoError(t, os.WriteFile(envFile, []byte("export EXISTING='1'\n"), 0644))
+
+	var normalizer = NewNormalizer()
+	output, err := normalizer.Propagate(
+		map[string]string{"BASH_ENV": envFile},
+		map[string]string{"NCI_COMMIT_TITLE": "it's $(id)"},
+		api.PropagateOptions{},
+	)
+
+	assert.NoError(t, err)
+	assert.Empty(t, output)
+	content, err := os.ReadFile(envFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "export EXISTING='1'\nexport NCI_COMMIT_TITLE='it'\\''s $(id)'\n", string(content))
+}
@@ -0,0 +1,113 @@
+package normalizer
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/cidverse/normalizeci/pkg/normalizer/normalizertest"
+	"github.com/stretchr/testify/assert"
+)
+
+// volatileFields are detected at runtime by every normalizer and can't be transported
+var volatileFields = []string{"NCI_PROJECT_DIR", "NCI_WORKER_ARCH", "NCI_WORKER_TYPE", "NCI_PIPELINE_JOB_STARTED_AT"}
+
+// lossyFields can't be transported, because the native environment of the ci system doesn't contain them
+var lossyFields = map[string][]string{
+	"appveyor": {
+		// no stages, deploy freeze, config file or worker details, the short hash is derived from the commit hash
+		"NCI_PIPELINE_STAGE_ID", "NCI_PIPELINE_STAGE_NAME", "NCI_PIPELINE_STAGE_SLUG", "NCI_PIPELINE_CONFIG_FILE", "NCI_DEPLOY_FREEZE",
+		"NCI_WORKER_ID", "NCI_WORKER_NAME", "NCI_WORKER_VERSION", "NCI_COMMIT_HASH_SHORT",
+		// the target commit of pull requests isn't exposed
+		"NCI_MERGE_REQUEST_TARGET_HASH",
+	},
+	"azure-devops": {
+		// no deploy freeze or config file, the title and target commit of pull requests aren't exposed
+		"NCI_DEPLOY_FREEZE", "NCI_PIPELINE_CONFIG_FILE", "NCI_MERGE_REQUEST_TITLE", "NCI_MERGE_REQUEST_TARGET_HASH",
+	},
+	"circleci": {
+		// no deploy freeze, attempt, config file or stage name, the worker is only identified by the node index
+		"NCI_DEPLOY_FREEZE", "NCI_PIPELINE_ATTEMPT", "NCI_PIPELINE_CONFIG_FILE", "NCI_PIPELINE_STAGE_NAME", "NCI_PIPELINE_STAGE_SLUG",
+		"NCI_WORKER_NAME", "NCI_WORKER_OS", "NCI_WORKER_VERSION",
+		// pull requests are only exposed as url and source branch
+		"NCI_MERGE_REQUEST_TITLE", "NCI_MERGE_REQUEST_SOURCE_HASH", "NCI_MERGE_REQUEST_TARGET_BRANCH_NAME", "NCI_MERGE_REQUEST_TARGET_HASH",
+	},
+	"generic": {
+		// the conventions only cover the pipeline id, job name, commit and pull request, everything else is taken from the local repository
+		"NCI_DEPLOY_FREEZE", "NCI_PIPELINE_ATTEMPT", "NCI_PIPELINE_CONFIG_FILE",
+		"NCI_PIPELINE_STAGE_ID", "NCI_PIPELINE_STAGE_NAME", "NCI_PIPELINE_STAGE_SLUG", "NCI_PIPELINE_JOB_ID",
+		"NCI_WORKER_ID", "NCI_WORKER_NAME", "NCI_WORKER_OS", "NCI_WORKER_VERSION",
+		"NCI_MERGE_REQUEST_SOURCE_HASH", "NCI_MERGE_REQUEST_TARGET_HASH",
+	},
+	"github-actions": {
+		// no deploy freeze or runner version, workflow and job ids are only available through the api
+		"NCI_DEPLOY_FREEZE", "NCI_WORKER_VERSION", "NCI_PIPELINE_STAGE_ID", "NCI_PIPELINE_JOB_ID",
+		// the title and commits of pull requests are only part of the event payload (GITHUB_EVENT_PATH)
+		"NCI_MERGE_REQUEST_TITLE", "NCI_MERGE_REQUEST_SOURCE_HASH", "NCI_MERGE_REQUEST_TARGET_HASH",
+	},
+	"gitlab-ci": {
+		// retries are new jobs, stages have no id and the runner only exposes its architecture
+		"NCI_PIPELINE_ATTEMPT", "NCI_PIPELINE_STAGE_ID", "NCI_WORKER_OS",
+	},
+}
+
+// explicitNormalizers are never detected and only used if requested explicitly, the round trip skips their Check
+var explicitNormalizers = []string{"generic"}
+
+// explicitNormalizer accepts every environment, see explicitNormalizers
+type explicitNormalizer struct {
+	api.Normalizer
+}
+
+func (n explicitNormalizer) Check(env map[string]string) bool {
+	return true
+}
+
+func TestRandomSpec(t *testing.T) {
+	for _, n := range GetNormalizers() {
+		for seed := int64(1); seed <= 25; seed++ {
+			spec := normalizertest.RandomSpec(rand.New(rand.NewSource(seed)), n)
+			assert.Empty(t, spec.Validate(), "%s: seed %d", n.GetSlug(), seed)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, n := range GetNormalizers() {
+		t.Run(n.GetSlug(), func(t *testing.T) {
+			if !n.SupportsDenormalize() {
+				t.Skipf("%s does not support denormalize", n.GetName())
+			}
+			if slices.Contains(explicitNormalizers, n.GetSlug()) {
+				n = explicitNormalizer{Normalizer: n}
+			}
+
+			for seed := int64(1); seed <= 25; seed++ {
+				spec := normalizertest.RandomSpec(rand.New(rand.NewSource(seed)), n)
+				differences, err := normalizertest.RoundTrip(t, n, spec)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				for _, difference := range differences {
+					if slices.Contains(volatileFields, difference.Field) || slices.Contains(lossyFields[n.GetSlug()], difference.Field) {
+						continue
+					}
+					t.Errorf("seed %d: %s", seed, difference)
+				}
+			}
+		})
+	}
+}
+
+func TestRoundTrip_NotSupported(t *testing.T) {
+	for _, n := range GetNormalizers() {
+		if n.SupportsDenormalize() {
+			continue
+		}
+
+		_, err := normalizertest.RoundTrip(t, n, normalizertest.RandomSpec(rand.New(rand.NewSource(1)), n))
+		assert.ErrorIs(t, err, normalizertest.ErrDenormalizeNotSupported)
+	}
+}
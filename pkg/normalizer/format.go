@@ -0,0 +1,177 @@
+package normalizer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+)
+
+func setNormalizedEnvironmentExport(normalized map[string]string) (string, error) {
+	keys, err := nciutil.SortedEnvKeys(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("export %s=%s\n", key, nciutil.QuotePosix(normalized[key])))
+	}
+
+	return sb.String(), nil
+}
+
+func setNormalizedEnvironmentPowershell(normalized map[string]string) (string, error) {
+	keys, err := nciutil.SortedEnvKeys(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("$env:%s=%s;\n", key, quotePowershell(normalized[key])))
+	}
+
+	return sb.String(), nil
+}
+
+func setNormalizedEnvironmentCmd(normalized map[string]string) (string, error) {
+	return setNormalizedEnvironmentCmdWith(normalized, cmdEscaper)
+}
+
+func setNormalizedEnvironmentCmdBatch(normalized map[string]string) (string, error) {
+	return setNormalizedEnvironmentCmdWith(normalized, cmdBatchEscaper)
+}
+
+func setNormalizedEnvironmentCmdWith(normalized map[string]string, escaper *strings.Replacer) (string, error) {
+	keys, err := nciutil.SortedEnvKeys(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("set %s=%s\n", key, escaper.Replace(normalized[key])))
+	}
+
+	return sb.String(), nil
+}
+
+func setNormalizedEnvironmentFish(normalized map[string]string) (string, error) {
+	keys, err := nciutil.SortedEnvKeys(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("set -gx %s %s;\n", key, quoteFish(normalized[key])))
+	}
+
+	return sb.String(), nil
+}
+
+func setNormalizedEnvironmentNushell(normalized map[string]string) (string, error) {
+	keys, err := nciutil.SortedEnvKeys(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("$env.%s = %s\n", key, quoteNushell(normalized[key])))
+	}
+
+	return sb.String(), nil
+}
+
+func setNormalizedEnvironmentCsh(normalized map[string]string) (string, error) {
+	keys, err := nciutil.SortedEnvKeys(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("setenv %s %s;\n", key, quoteCsh(normalized[key])))
+	}
+
+	return sb.String(), nil
+}
+
+// fishEscaper escapes the only two characters that are interpreted within a single-quoted fish string
+var fishEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"'", `\'`,
+)
+
+// quoteFish wraps the value in single quotes, line breaks are kept as is
+func quoteFish(value string) string {
+	return "'" + fishEscaper.Replace(value) + "'"
+}
+
+// nushellEscaper escapes a value within a double-quoted nushell string, double-quoted strings are not interpolated in nushell
+var nushellEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\r", `\r`,
+	"\n", `\n`,
+	"\t", `\t`,
+)
+
+// quoteNushell wraps the value in double quotes, line breaks are encoded
+func quoteNushell(value string) string {
+	return `"` + nushellEscaper.Replace(value) + `"`
+}
+
+// cshEscaper escapes a value within a single-quoted csh string, history substitution (!) also applies within single quotes and line breaks must be escaped
+var cshEscaper = strings.NewReplacer(
+	"'", `'\''`,
+	"!", `\!`,
+	"\n", "\\\n",
+)
+
+// quoteCsh wraps the value in single quotes
+func quoteCsh(value string) string {
+	return "'" + cshEscaper.Replace(value) + "'"
+}
+
+// powershellEscaper escapes all characters that are interpreted within a double-quoted PowerShell string, PowerShell also treats the typographic quotes as double quotes
+var powershellEscaper = strings.NewReplacer(
+	"`", "``",
+	"$", "`$",
+	`"`, "`\"",
+	"“", "`“",
+	"”", "`”",
+	"„", "`„",
+	"\r", "`r",
+	"\n", "`n",
+)
+
+// quotePowershell wraps the value in double quotes, newlines are encoded so every assignment stays on a single line
+func quotePowershell(value string) string {
+	return `"` + powershellEscaper.Replace(value) + `"`
+}
+
+// cmdEscapes escape the cmd metacharacters with a caret.
+// cmd has no way to represent a line break in a variable, so line breaks are replaced by a space.
+// Note: exclamation marks are only special with delayed expansion enabled, which is off by default and therefore not escaped.
+var cmdEscapes = []string{
+	"^", "^^",
+	"&", "^&",
+	"|", "^|",
+	"<", "^<",
+	">", "^>",
+	"(", "^(",
+	")", "^)",
+	`"`, `^"`,
+	"\r\n", " ",
+	"\r", " ",
+	"\n", " ",
+}
+
+// cmdEscaper is used for the interactive prompt, which has no escape for the percent sign, so variable references in values are expanded
+var cmdEscaper = strings.NewReplacer(cmdEscapes...)
+
+// cmdBatchEscaper is used for batch files (.bat, .cmd), which additionally require the percent sign to be doubled
+var cmdBatchEscaper = strings.NewReplacer(append([]string{"%", "%%"}, cmdEscapes...)...)
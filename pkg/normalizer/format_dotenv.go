@@ -0,0 +1,68 @@
+package normalizer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/gitlabci"
+)
+
+// dotenvEscaper escapes a value within a double-quoted dotenv value
+var dotenvEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\r", `\r`,
+	"\n", `\n`,
+)
+
+// setNormalizedEnvironmentDotenv renders a .env file as read by dotenv libraries and docker compose.
+// Values are single-quoted (no escapes or interpolation), values that contain single quotes or line breaks are double-quoted with escapes instead.
+func setNormalizedEnvironmentDotenv(normalized map[string]string) (string, error) {
+	keys, err := nciutil.SortedEnvKeys(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		value := normalized[key]
+		if strings.ContainsAny(value, "'\r\n") {
+			sb.WriteString(fmt.Sprintf("%s=\"%s\"\n", key, dotenvEscaper.Replace(value)))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s='%s'\n", key, value))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// setNormalizedEnvironmentDockerEnv renders a file for `docker run --env-file`, which takes everything after the = literally.
+// Line breaks can't be represented and are encoded as \n.
+func setNormalizedEnvironmentDockerEnv(normalized map[string]string) (string, error) {
+	keys, err := nciutil.SortedEnvKeys(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("%s=%s\n", key, nciutil.EncodeLineBreaks(normalized[key])))
+	}
+
+	return sb.String(), nil
+}
+
+// formatGitlabDotenv renders a GitLab dotenv report, the options max-variables and max-size override the GitLab.com limits (e.g. for self-managed instances)
+func formatGitlabDotenv(input FormatInput) (string, error) {
+	maxVariables, err := input.IntOption("max-variables", gitlabci.DotenvMaxVariables)
+	if err != nil {
+		return "", err
+	}
+	maxSize, err := input.IntOption("max-size", gitlabci.DotenvMaxSize)
+	if err != nil {
+		return "", err
+	}
+
+	return gitlabci.FormatDotenv(input.Env, gitlabci.DotenvLimits{MaxVariables: maxVariables, MaxSize: maxSize})
+}
@@ -0,0 +1,77 @@
+package normalizer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer/gitlabci"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatEnvironment_Dotenv(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{
+		"NCI_A": "plain value with $HOME and \"quotes\"",
+		"NCI_B": "it's",
+		"NCI_C": "title\n\nbody with \\ and \"",
+	}, "dotenv")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "NCI_A='plain value with $HOME and \"quotes\"'\nNCI_B=\"it's\"\nNCI_C=\"title\\n\\nbody with \\\\ and \\\"\"\n", content)
+}
+
+func TestFormatEnvironment_DockerEnv(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{
+		"NCI_A": "\"quoted\" 'value' $HOME",
+		"NCI_B": "title\r\nbody",
+	}, "docker-env")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "NCI_A=\"quoted\" 'value' $HOME\nNCI_B=title\\r\\nbody\n", content)
+}
+
+func TestFormatEnvironment_GitlabDotenv(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{
+		"NCI_A": "value with spaces",
+		"NCI_B": "title\nbody",
+	}, "gitlab-dotenv")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "NCI_A=value with spaces\nNCI_B=title\\nbody\n", content)
+}
+
+func TestFormatEnvironment_GitlabDotenvLimits(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := 0; i <= gitlabci.DotenvMaxVariables; i++ {
+		tooMany[fmt.Sprintf("NCI_%d", i)] = "x"
+	}
+	_, err := FormatEnvironment(tooMany, "gitlab-dotenv")
+	assert.ErrorContains(t, err, "variables")
+
+	_, err = FormatEnvironment(map[string]string{"NCI_A": strings.Repeat("x", gitlabci.DotenvMaxSize)}, "gitlab-dotenv")
+	assert.ErrorContains(t, err, "bytes")
+}
+
+func TestFormat_GitlabDotenvLimitOptions(t *testing.T) {
+	env := map[string]string{"NCI_A": "a", "NCI_B": "b", "NCI_C": "c"}
+
+	_, err := Format(FormatInput{Env: env, Options: map[string]string{"max-variables": "2"}}, "gitlab-dotenv")
+	assert.ErrorContains(t, err, "limited to 2 variables")
+
+	_, err = Format(FormatInput{Env: env, Options: map[string]string{"max-size": "10"}}, "gitlab-dotenv")
+	assert.ErrorContains(t, err, "limited to 10 bytes")
+
+	_, err = Format(FormatInput{Env: env, Options: map[string]string{"max-variables": "many"}}, "gitlab-dotenv")
+	assert.ErrorContains(t, err, "max-variables")
+
+	content, err := Format(FormatInput{Env: env, Options: map[string]string{"max-variables": "3", "max-size": "24"}}, "gitlab-dotenv")
+	assert.NoError(t, err)
+	assert.Equal(t, "NCI_A=a\nNCI_B=b\nNCI_C=c\n", content)
+}
+
+func TestFormatEnvironment_DotenvInvalidKey(t *testing.T) {
+	for _, format := range []string{"dotenv", "docker-env", "gitlab-dotenv"} {
+		_, err := FormatEnvironment(map[string]string{"# comment": "value"}, format)
+		assert.Error(t, err, format)
+	}
+}
@@ -0,0 +1,127 @@
+package normalizer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+)
+
+// kubernetesLabelMaxLength is the maximum length of label values and name segments, see https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#syntax-and-character-set
+const kubernetesLabelMaxLength = 63
+
+// kubernetesNameMaxLength is the maximum length of object names (DNS subdomain)
+const kubernetesNameMaxLength = 253
+
+var (
+	kubernetesLabelInvalidChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	kubernetesNameInvalidChars  = regexp.MustCompile(`[^a-z0-9.-]+`)
+)
+
+type kubernetesConfigMap struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   kubernetesMetadata `yaml:"metadata"`
+	Data       map[string]string  `yaml:"data"`
+}
+
+type kubernetesMetadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// formatKubernetesConfigMap renders a ConfigMap containing all variables, supports the options name and namespace.
+// Labels and annotations are derived from the normalized variables, label values are clipped to the kubernetes rules.
+func formatKubernetesConfigMap(input FormatInput) (string, error) {
+	if _, err := nciutil.SortedEnvKeys(input.Env); err != nil {
+		return "", err
+	}
+
+	name := kubernetesName(input.Option("name", "nci-"+input.Env["NCI_PROJECT_SLUG"]))
+	if name == "" {
+		name = "nci"
+	}
+
+	labels := make(map[string]string)
+	for key, envKey := range map[string]string{
+		"app.kubernetes.io/name":    "NCI_PROJECT_SLUG",
+		"app.kubernetes.io/version": "NCI_COMMIT_REF_RELEASE",
+		"normalize.ci/ref":          "NCI_COMMIT_REF_SLUG",
+		"normalize.ci/commit":       "NCI_COMMIT_HASH_SHORT",
+	} {
+		if value := kubernetesLabelValue(input.Env[envKey]); value != "" {
+			labels[key] = value
+		}
+	}
+	labels["app.kubernetes.io/managed-by"] = "normalizeci"
+
+	annotations := make(map[string]string)
+	for key, envKey := range map[string]string{
+		"normalize.ci/commit-hash":  "NCI_COMMIT_HASH",
+		"normalize.ci/pipeline-url": "NCI_PIPELINE_URL",
+		"normalize.ci/project-url":  "NCI_PROJECT_URL",
+	} {
+		if value := input.Env[envKey]; value != "" {
+			annotations[key] = value
+		}
+	}
+
+	return marshalDocument(kubernetesConfigMap{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata: kubernetesMetadata{
+			Name:        name,
+			Namespace:   kubernetesNamespace(input.Option("namespace", "")),
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Data: input.Env,
+	}, "yaml")
+}
+
+// formatHelmValues renders the spec as values file, nested under the key option (default nci), which may contain dots for deeper nesting
+func formatHelmValues(input FormatInput) (string, error) {
+	var values interface{} = input.Env
+	if input.Spec != nil {
+		values = *input.Spec
+	}
+
+	path := strings.Split(input.Option("key", "nci"), ".")
+	for i := len(path) - 1; i >= 0; i-- {
+		values = map[string]interface{}{path[i]: values}
+	}
+
+	return marshalDocument(values, "yaml")
+}
+
+// kubernetesLabelValue converts the value into a valid label value: at most 63 characters, alphanumeric, '-', '_' or '.' and beginning and ending with an alphanumeric character
+func kubernetesLabelValue(value string) string {
+	value = kubernetesLabelInvalidChars.ReplaceAllString(value, "-")
+	if len(value) > kubernetesLabelMaxLength {
+		value = value[:kubernetesLabelMaxLength]
+	}
+
+	return strings.Trim(value, "-_.")
+}
+
+// kubernetesName converts the value into a valid object name (DNS subdomain): at most 253 lowercase alphanumeric characters, '-' or '.', beginning and ending with an alphanumeric character
+func kubernetesName(value string) string {
+	value = kubernetesNameInvalidChars.ReplaceAllString(strings.ToLower(value), "-")
+	if len(value) > kubernetesNameMaxLength {
+		value = value[:kubernetesNameMaxLength]
+	}
+
+	return strings.Trim(value, "-.")
+}
+
+// kubernetesNamespace converts the value into a valid namespace (DNS label): at most 63 lowercase alphanumeric characters or '-', beginning and ending with an alphanumeric character
+func kubernetesNamespace(value string) string {
+	value = kubernetesName(strings.ReplaceAll(value, ".", "-"))
+	if len(value) > kubernetesLabelMaxLength {
+		value = value[:kubernetesLabelMaxLength]
+	}
+
+	return strings.Trim(value, "-")
+}
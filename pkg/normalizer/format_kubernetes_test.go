@@ -0,0 +1,88 @@
+package normalizer
+
+import (
+	"strings"
+	"testing"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFormat_KubernetesConfigMap(t *testing.T) {
+	content, err := Format(FormatInput{
+		Env: map[string]string{
+			"NCI_PROJECT_SLUG":       "my-project",
+			"NCI_COMMIT_REF_RELEASE": "1.2.3",
+			"NCI_COMMIT_HASH":        "f1e2d3c4b5a6",
+			"NCI_COMMIT_TITLE":       "title: with\nline break",
+		},
+		Options: map[string]string{"namespace": "Preview.Env"},
+	}, "k8s-configmap")
+
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: nci-my-project
+  namespace: preview-env
+  labels:
+    app.kubernetes.io/managed-by: normalizeci
+    app.kubernetes.io/name: my-project
+    app.kubernetes.io/version: 1.2.3
+  annotations:
+    normalize.ci/commit-hash: f1e2d3c4b5a6
+data:
+  NCI_COMMIT_HASH: f1e2d3c4b5a6
+  NCI_COMMIT_REF_RELEASE: 1.2.3
+  NCI_COMMIT_TITLE: |-
+    title: with
+    line break
+  NCI_PROJECT_SLUG: my-project
+`, content)
+}
+
+func TestFormat_KubernetesConfigMapClipping(t *testing.T) {
+	longSlug := strings.Repeat("a", 62) + "-bcd"
+	content, err := Format(FormatInput{
+		Env:     map[string]string{"NCI_PROJECT_SLUG": longSlug, "NCI_COMMIT_REF_SLUG": "feature/ABC_1"},
+		Options: map[string]string{"name": "My ConfigMap!"},
+	}, "k8s-configmap")
+	assert.NoError(t, err)
+
+	var configMap kubernetesConfigMap
+	assert.NoError(t, yaml.Unmarshal([]byte(content), &configMap))
+	assert.Equal(t, "my-configmap", configMap.Metadata.Name)
+	assert.Equal(t, strings.Repeat("a", 62), configMap.Metadata.Labels["app.kubernetes.io/name"])
+	assert.Equal(t, "feature-ABC_1", configMap.Metadata.Labels["normalize.ci/ref"])
+}
+
+func TestKubernetesSanitize(t *testing.T) {
+	assert.Equal(t, "abc", kubernetesLabelValue("-_abc._"))
+	assert.Equal(t, "", kubernetesLabelValue("---"))
+	assert.Len(t, kubernetesLabelValue(strings.Repeat("x", 100)), 63)
+	assert.Equal(t, "a.b-c", kubernetesName(".A.b C-"))
+	assert.Len(t, kubernetesName(strings.Repeat("x", 300)), 253)
+	assert.Equal(t, "a-b", kubernetesNamespace("a.b"))
+	assert.Len(t, kubernetesNamespace(strings.Repeat("x", 100)), 63)
+}
+
+func TestFormat_HelmValues(t *testing.T) {
+	spec := v1.Create("GitHub Actions", "github-actions")
+	spec.Commit.Hash = "f1e2d3c4b5a6"
+
+	content, err := Format(FormatInput{Spec: &spec, Options: map[string]string{"key": "global.build"}}, "helm-values")
+	assert.NoError(t, err)
+
+	var values map[string]map[string]v1.Spec
+	assert.NoError(t, yaml.Unmarshal([]byte(content), &values))
+	assert.Equal(t, "f1e2d3c4b5a6", values["global"]["build"].Commit.Hash)
+	assert.True(t, strings.HasPrefix(content, "global:\n  build:\n    found: \"true\"\n"))
+}
+
+func TestFormat_HelmValuesEnv(t *testing.T) {
+	content, err := Format(FormatInput{Env: map[string]string{"NCI_COMMIT_HASH": "abc"}}, "helm-values")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "nci:\n  NCI_COMMIT_HASH: abc\n", content)
+}
@@ -0,0 +1,155 @@
+package normalizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+)
+
+// key styles for formats that don't use environment variable names, see propertyKey
+const (
+	KeyStyleDotted = "dotted" // nci.commit.hash
+	KeyStyleCamel  = "camel"  // nciCommitHash
+	KeyStyleKebab  = "kebab"  // nci-commit-hash
+	KeyStyleEnv    = "env"    // NCI_COMMIT_HASH
+)
+
+// npmEnvExpr matches the ${NAME} expressions that npm replaces with environment variables in .npmrc values, including preceding escapes
+var npmEnvExpr = regexp.MustCompile(`(\\*)\$\{([^${}?]+)\}`)
+
+// propertyFormatter creates a formatter for key-value formats, which supports the options key-style and key-prefix
+func propertyFormatter(defaultKeyStyle string, f func(keys []string, values map[string]string) (string, error)) Formatter {
+	return FormatterFunc(func(input FormatInput) (string, error) {
+		envKeys, err := nciutil.SortedEnvKeys(input.Env)
+		if err != nil {
+			return "", err
+		}
+
+		style := input.Option("key-style", defaultKeyStyle)
+		prefix := input.Option("key-prefix", "")
+		keys := make([]string, 0, len(envKeys))
+		values := make(map[string]string, len(envKeys))
+		for _, envKey := range envKeys {
+			key, err := propertyKey(envKey, style, prefix)
+			if err != nil {
+				return "", err
+			}
+			if _, exists := values[key]; exists {
+				return "", fmt.Errorf("variable %s results in duplicate key %s", envKey, key)
+			}
+			keys = append(keys, key)
+			values[key] = input.Env[envKey]
+		}
+
+		return f(keys, values)
+	})
+}
+
+// propertyKey converts the environment variable name into the key style, the prefix replaces the leading NCI of normalized variables
+func propertyKey(envKey string, style string, prefix string) (string, error) {
+	if prefix != "" && strings.HasPrefix(envKey, "NCI_") {
+		envKey = prefix + envKey[3:]
+	}
+
+	parts := strings.FieldsFunc(envKey, func(r rune) bool { return r == '_' })
+	switch style {
+	case KeyStyleEnv:
+		return envKey, nil
+	case KeyStyleDotted:
+		return strings.ToLower(strings.Join(parts, ".")), nil
+	case KeyStyleKebab:
+		return strings.ToLower(strings.Join(parts, "-")), nil
+	case KeyStyleCamel:
+		var sb strings.Builder
+		for i, part := range parts {
+			part = strings.ToLower(part)
+			if i > 0 {
+				part = strings.ToUpper(part[:1]) + part[1:]
+			}
+			sb.WriteString(part)
+		}
+		return sb.String(), nil
+	}
+
+	return "", fmt.Errorf("unsupported key-style %s, supported: %s, %s, %s, %s", style, KeyStyleDotted, KeyStyleCamel, KeyStyleKebab, KeyStyleEnv)
+}
+
+// setNormalizedEnvironmentJavaProperties renders a .properties file as read by java.util.Properties and gradle.properties.
+// Properties files are ISO-8859-1 encoded, so all other characters are written as \uXXXX escapes.
+func setNormalizedEnvironmentJavaProperties(keys []string, values map[string]string) (string, error) {
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(escapeJavaProperty(key, true) + "=" + escapeJavaProperty(values[key], false) + "\n")
+	}
+
+	return sb.String(), nil
+}
+
+// setNormalizedEnvironmentMavenArgs renders -Dkey=value system properties, quoted for POSIX shells
+func setNormalizedEnvironmentMavenArgs(keys []string, values map[string]string) (string, error) {
+	args := make([]string, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, nciutil.QuotePosix("-D"+key+"="+values[key]))
+	}
+
+	return strings.Join(args, " ") + "\n", nil
+}
+
+// setNormalizedEnvironmentNpmConfig renders a .npmrc file.
+// Values are written as json strings, which npm decodes when reading quoted values, ${NAME} expressions are escaped to prevent environment variable expansion.
+func setNormalizedEnvironmentNpmConfig(keys []string, values map[string]string) (string, error) {
+	var sb strings.Builder
+	for _, key := range keys {
+		value := npmEnvExpr.ReplaceAllStringFunc(values[key], func(expr string) string {
+			escapes := len(expr) - len(strings.TrimLeft(expr, `\`))
+			return strings.Repeat(`\`, escapes*2+1) + expr[escapes:]
+		})
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode value of %s: %w", key, err)
+		}
+		sb.WriteString(key + "=" + string(encoded) + "\n")
+	}
+
+	return sb.String(), nil
+}
+
+// escapeJavaProperty escapes a key or value for a .properties file, see java.util.Properties#store
+func escapeJavaProperty(value string, isKey bool) string {
+	var sb strings.Builder
+	for i, r := range value {
+		switch {
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\f':
+			sb.WriteString(`\f`)
+		case r == ' ' && (isKey || i == 0):
+			sb.WriteString(`\ `)
+		case strings.ContainsRune("=:#!", r):
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			if r > 0xffff {
+				r1, r2 := utf16.EncodeRune(r)
+				sb.WriteString(fmt.Sprintf(`\u%04X\u%04X`, r1, r2))
+			} else {
+				sb.WriteString(fmt.Sprintf(`\u%04X`, r))
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
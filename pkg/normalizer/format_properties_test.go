@@ -0,0 +1,69 @@
+package normalizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat_JavaProperties(t *testing.T) {
+	content, err := Format(FormatInput{Env: map[string]string{
+		"NCI_COMMIT_HASH":  "abc",
+		"NCI_COMMIT_TITLE": " leading space, a=b: #1 ! C:\\dir",
+		"NCI_PROJECT_NAME": "ümlaut 🚀\nline",
+	}}, "java-properties")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "nci.commit.hash=abc\nnci.commit.title=\\ leading space, a\\=b\\: \\#1 \\! C\\:\\\\dir\nnci.project.name=\\u00FCmlaut \\uD83D\\uDE80\\nline\n", content)
+}
+
+func TestFormat_GradleProperties(t *testing.T) {
+	content, err := Format(FormatInput{Env: map[string]string{"NCI_COMMIT_HASH": "abc"}}, "gradle-properties")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "nciCommitHash=abc\n", content)
+}
+
+func TestFormat_MavenArgs(t *testing.T) {
+	content, err := Format(FormatInput{
+		Env:     map[string]string{"NCI_COMMIT_HASH": "abc", "NCI_COMMIT_TITLE": "it's $HOME"},
+		Options: map[string]string{"key-prefix": "ci"},
+	}, "maven-args")
+
+	assert.NoError(t, err)
+	assert.Equal(t, `'-Dci.commit.hash=abc' '-Dci.commit.title=it'\''s $HOME'`+"\n", content)
+}
+
+func TestFormat_NpmConfig(t *testing.T) {
+	content, err := Format(FormatInput{
+		Env: map[string]string{
+			"NCI_COMMIT_TITLE":       "say \"hi\" ${HOME} \\${USER} ${",
+			"NCI_COMMIT_DESCRIPTION": "title\nbody",
+		},
+		Options: map[string]string{"key-style": "kebab"},
+	}, "npm-config")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "nci-commit-description=\"title\\nbody\"\nnci-commit-title=\"say \\\"hi\\\" \\\\${HOME} \\\\\\\\\\\\${USER} ${\"\n", content)
+}
+
+func TestFormat_PropertyKeyStyles(t *testing.T) {
+	for style, expected := range map[string]string{
+		KeyStyleDotted: "nci.commit.ref.name",
+		KeyStyleCamel:  "nciCommitRefName",
+		KeyStyleKebab:  "nci-commit-ref-name",
+		KeyStyleEnv:    "NCI_COMMIT_REF_NAME",
+	} {
+		key, err := propertyKey("NCI_COMMIT_REF_NAME", style, "")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, key, style)
+	}
+
+	_, err := Format(FormatInput{Env: map[string]string{"NCI_A": "x"}, Options: map[string]string{"key-style": "snake"}}, "java-properties")
+	assert.ErrorContains(t, err, "key-style")
+}
+
+func TestFormat_PropertyDuplicateKeys(t *testing.T) {
+	_, err := Format(FormatInput{Env: map[string]string{"NCI_A_B": "x", "NCI_A__B": "y"}}, "java-properties")
+	assert.ErrorContains(t, err, "duplicate")
+}
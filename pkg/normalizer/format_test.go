@@ -0,0 +1,168 @@
+package normalizer
+
+import (
+	"math/rand"
+	"os/exec"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	"github.com/cidverse/normalizeci/pkg/normalizer/normalizertest"
+	"github.com/stretchr/testify/assert"
+)
+
+var adversarialValues = map[string]string{
+	"NCI_SUBSHELL":  "$(touch /tmp/normalizeci-pwned)",
+	"NCI_BACKTICK":  "`touch /tmp/normalizeci-pwned`",
+	"NCI_VARIABLE":  "${HOME} $HOME %PATH% !PATH!",
+	"NCI_BACKSLASH": `C:\path\to\ \"quoted\" \\`,
+	"NCI_QUOTES":    `it's a "test" ‘single’ “double”`,
+	"NCI_MULTILINE": "feat: title\n\nbody line 1\r\nbody line 2",
+	"NCI_METACHARS": "a & b | c < d > e ; f ( g ) ^ h",
+}
+
+func TestFormatEnvironment_Export(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{"NCI_B": "b", "NCI_A": "it's $(id)"}, "export")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "export NCI_A='it'\\''s $(id)'\nexport NCI_B='b'\n", content)
+}
+
+func TestFormatEnvironment_ExportEval(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+
+	content, err := FormatEnvironment(adversarialValues, "export")
+	assert.NoError(t, err)
+
+	for key, value := range adversarialValues {
+		out, err := exec.Command(sh, "-c", "eval \"$1\"; printf '%s' \"$(printenv "+key+")\"", "sh", content).Output()
+		assert.NoError(t, err)
+		assert.Equal(t, value, string(out), key)
+	}
+}
+
+func TestFormatEnvironment_Powershell(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{
+		"NCI_A": "$(Remove-Item x) `whoami` \"quoted\" “smart”",
+		"NCI_B": "line1\r\nline2",
+	}, "powershell")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "$env:NCI_A=\"`$(Remove-Item x) ``whoami`` `\"quoted`\" `“smart`”\";\n$env:NCI_B=\"line1`r`nline2\";\n", content)
+}
+
+func TestFormatEnvironment_Cmd(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{
+		"NCI_A": "a & calc | b > c < d ^ (e) \"f\" %PATH%",
+		"NCI_B": "line1\r\nline2\nline3",
+	}, "cmd")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "set NCI_A=a ^& calc ^| b ^> c ^< d ^^ ^(e^) ^\"f^\" %PATH%\nset NCI_B=line1 line2 line3\n", content)
+}
+
+func TestFormatEnvironment_CmdBatch(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{
+		"NCI_A": "a & calc | b > c < d ^ (e) \"f\" %PATH%",
+		"NCI_B": "line1\r\nline2\nline3",
+	}, "cmd-batch")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "set NCI_A=a ^& calc ^| b ^> c ^< d ^^ ^(e^) ^\"f^\" %%PATH%%\nset NCI_B=line1 line2 line3\n", content)
+}
+
+func TestFormatEnvironment_Fish(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{"NCI_A": `it's $HOME (x) \ `, "NCI_B": "line1\nline2"}, "fish")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "set -gx NCI_A 'it\\'s $HOME (x) \\\\ ';\nset -gx NCI_B 'line1\nline2';\n", content)
+}
+
+func TestFormatEnvironment_Nushell(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{"NCI_A": `say "hi" $(id) \`, "NCI_B": "line1\r\nline2"}, "nushell")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "$env.NCI_A = \"say \\\"hi\\\" $(id) \\\\\"\n$env.NCI_B = \"line1\\r\\nline2\"\n", content)
+}
+
+func TestFormatEnvironment_Csh(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{"NCI_A": "it's $HOME !!", "NCI_B": "line1\nline2"}, "csh")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "setenv NCI_A 'it'\\''s $HOME \\!\\!';\nsetenv NCI_B 'line1\\\nline2';\n", content)
+}
+
+func TestFormatEnvironment_InvalidKey(t *testing.T) {
+	for _, format := range []string{"export", "powershell", "cmd", "cmd-batch", "fish", "nushell", "csh"} {
+		_, err := FormatEnvironment(map[string]string{"NCI_A; rm -rf ~": "value"}, format)
+		assert.Error(t, err, format)
+
+		_, err = FormatEnvironment(map[string]string{"NCI_A": "a\x00b"}, format)
+		assert.Error(t, err, format)
+	}
+}
+
+func TestFormatEnvironment_HyphenatedInput(t *testing.T) {
+	spec := normalizertest.RandomSpec(rand.New(rand.NewSource(1)), GetNormalizers()[0])
+	spec.Pipeline.Input = map[string]string{"log-level": "debug"}
+	env := envstruct.StructToEnvMap(spec)
+
+	for _, format := range []string{"export", "powershell", "cmd", "cmd-batch", "fish", "nushell", "csh", "dotenv", "gitlab-dotenv"} {
+		content, err := FormatEnvironment(env, format)
+		assert.NoError(t, err, format)
+		assert.Contains(t, content, "NCI_INPUT_LOG_LEVEL", format)
+	}
+}
+
+func TestFormatEnvironment_Unsupported(t *testing.T) {
+	_, err := FormatEnvironment(map[string]string{}, "unknown")
+	assert.Error(t, err)
+}
+
+func TestFormatSpec_JSON(t *testing.T) {
+	spec := normalizertest.RandomSpec(rand.New(rand.NewSource(1)), GetNormalizers()[0])
+	spec.Pipeline.Input = map[string]string{"environment": "production"}
+
+	content, err := FormatSpec(spec, "json")
+	assert.NoError(t, err)
+	assert.Contains(t, content, `"mergeRequest": {`)
+	assert.Contains(t, content, `"refVcs": "`+spec.Commit.RefVCS+`"`)
+	assert.Contains(t, content, `"inputs": {`)
+
+	parsed, err := ParseSpecJSON([]byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, spec, parsed)
+}
+
+func TestFormatSpec_YAML(t *testing.T) {
+	spec := normalizertest.RandomSpec(rand.New(rand.NewSource(1)), GetNormalizers()[0])
+
+	content, err := FormatSpec(spec, "yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, content, "serviceSlug: "+spec.ServiceSlug+"\n")
+	assert.Contains(t, content, "\ncommit:\n  refType: "+spec.Commit.RefType+"\n")
+	assert.NotContains(t, content, "inputs:")
+}
+
+func TestFormatSpec_Flat(t *testing.T) {
+	spec := normalizertest.RandomSpec(rand.New(rand.NewSource(1)), GetNormalizers()[0])
+
+	content, err := FormatSpec(spec, "export")
+	assert.NoError(t, err)
+	expected, _ := FormatEnvironment(envstruct.StructToEnvMap(spec), "export")
+	assert.Equal(t, expected, content)
+}
+
+func TestFormatEnvironment_JSON(t *testing.T) {
+	content, err := FormatEnvironment(map[string]string{"NCI_B": "b", "NCI_A": "a"}, "json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"NCI_A\": \"a\",\n  \"NCI_B\": \"b\"\n}\n", content)
+}
+
+func TestParseSpecJSON_Invalid(t *testing.T) {
+	_, err := ParseSpecJSON([]byte("{"))
+	assert.Error(t, err)
+}
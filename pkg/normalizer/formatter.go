@@ -0,0 +1,221 @@
+package normalizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"gopkg.in/yaml.v3"
+)
+
+// Formatter renders the normalized data in a specific format
+type Formatter interface {
+	Format(input FormatInput) (string, error)
+}
+
+// FormatInput is the data passed to a Formatter
+type FormatInput struct {
+	Spec    *v1.Spec          // Spec is the normalized spec, nil if only an environment is available (e.g. denormalize)
+	Env     map[string]string // Env contains all variables to render, including the variables of additional targets
+	Options map[string]string // Options holds format specific settings, e.g. key-style
+}
+
+// Option returns the value of a format option, or the fallback if the option isn't set
+func (input FormatInput) Option(name string, fallback string) string {
+	if value, ok := input.Options[name]; ok && value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// IntOption returns the value of a numeric format option, or the fallback if the option isn't set
+func (input FormatInput) IntOption(name string, fallback int) (int, error) {
+	value := input.Option(name, "")
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("format option %s must be a positive number, got %q", name, value)
+	}
+
+	return number, nil
+}
+
+// FormatterFunc allows the use of ordinary functions as Formatter
+type FormatterFunc func(input FormatInput) (string, error)
+
+// Format calls f(input)
+func (f FormatterFunc) Format(input FormatInput) (string, error) {
+	return f(input)
+}
+
+// holds all known formatters
+var (
+	formatters   = make(map[string]Formatter)
+	formattersMu sync.RWMutex
+)
+
+func init() {
+	RegisterFormatter("export", envFormatter(setNormalizedEnvironmentExport))
+	RegisterFormatter("powershell", envFormatter(setNormalizedEnvironmentPowershell))
+	RegisterFormatter("cmd", envFormatter(setNormalizedEnvironmentCmd))
+	RegisterFormatter("cmd-batch", envFormatter(setNormalizedEnvironmentCmdBatch))
+	RegisterFormatter("fish", envFormatter(setNormalizedEnvironmentFish))
+	RegisterFormatter("nushell", envFormatter(setNormalizedEnvironmentNushell))
+	RegisterFormatter("csh", envFormatter(setNormalizedEnvironmentCsh))
+	RegisterFormatter("dotenv", envFormatter(setNormalizedEnvironmentDotenv))
+	RegisterFormatter("docker-env", envFormatter(setNormalizedEnvironmentDockerEnv))
+	RegisterFormatter("gitlab-dotenv", FormatterFunc(formatGitlabDotenv))
+	RegisterFormatter("java-properties", propertyFormatter(KeyStyleDotted, setNormalizedEnvironmentJavaProperties))
+	RegisterFormatter("gradle-properties", propertyFormatter(KeyStyleCamel, setNormalizedEnvironmentJavaProperties))
+	RegisterFormatter("maven-args", propertyFormatter(KeyStyleDotted, setNormalizedEnvironmentMavenArgs))
+	RegisterFormatter("npm-config", propertyFormatter(KeyStyleDotted, setNormalizedEnvironmentNpmConfig))
+	RegisterFormatter("k8s-configmap", FormatterFunc(formatKubernetesConfigMap))
+	RegisterFormatter("helm-values", FormatterFunc(formatHelmValues))
+	RegisterFormatter("json", documentFormatter("json"))
+	RegisterFormatter("yaml", documentFormatter("yaml"))
+}
+
+// RegisterFormatter adds a formatter to the registry, an existing formatter with the same name is replaced
+func RegisterFormatter(name string, formatter Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+
+	formatters[name] = formatter
+}
+
+// GetFormatter returns the formatter registered for the name
+func GetFormatter(name string) (Formatter, bool) {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+
+	formatter, ok := formatters[name]
+	return formatter, ok
+}
+
+// GetFormats returns the names of all registered formatters, sorted by name
+func GetFormats() []string {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Format renders the input using the formatter registered for the format
+func Format(input FormatInput, format string) (string, error) {
+	formatter, ok := GetFormatter(format)
+	if !ok {
+		return "", errors.New("unsupported format: " + format)
+	}
+
+	return formatter.Format(input)
+}
+
+// FormatEnvironment makes the normalized environment available in the current session
+func FormatEnvironment(normalized map[string]string, format string) (string, error) {
+	return Format(FormatInput{Env: normalized}, format)
+}
+
+// FormatSpec renders the spec as nested json or yaml document, other formats receive the flat environment
+func FormatSpec(spec v1.Spec, format string) (string, error) {
+	return Format(FormatInput{Spec: &spec, Env: envstruct.StructToEnvMap(spec)}, format)
+}
+
+// ParseSpecJSON loads a spec from a json document generated by FormatSpec
+func ParseSpecJSON(data []byte) (v1.Spec, error) {
+	var spec v1.Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return spec, fmt.Errorf("failed to parse spec: %w", err)
+	}
+
+	return spec, nil
+}
+
+// IsStructuredFormat returns true for document formats, that keep the nested structure of the spec
+func IsStructuredFormat(format string) bool {
+	return format == "json" || format == "yaml" || format == "helm-values"
+}
+
+// GetDefaultFormat returns the format matching the shell of the current user
+func GetDefaultFormat() string {
+	return defaultFormat(runtime.GOOS, os.Getenv("SHELL"))
+}
+
+func defaultFormat(goos string, shell string) string {
+	// the shell may be a windows path (e.g. git bash), which isn't handled by filepath on other systems
+	switch strings.TrimSuffix(path.Base(strings.ReplaceAll(shell, `\`, "/")), ".exe") {
+	case "fish":
+		return "fish"
+	case "nu":
+		return "nushell"
+	case "csh", "tcsh":
+		return "csh"
+	case "sh", "bash", "zsh", "ksh", "dash", "ash":
+		return "export"
+	}
+
+	if goos == "windows" {
+		return "powershell"
+	}
+
+	return "export"
+}
+
+// envFormatter creates a formatter that renders the flat environment
+func envFormatter(f func(env map[string]string) (string, error)) Formatter {
+	return FormatterFunc(func(input FormatInput) (string, error) {
+		return f(input.Env)
+	})
+}
+
+// documentFormatter creates a formatter that renders the spec as document, or the flat environment if no spec is available
+func documentFormatter(format string) Formatter {
+	return FormatterFunc(func(input FormatInput) (string, error) {
+		if input.Spec != nil {
+			return marshalDocument(*input.Spec, format)
+		}
+
+		return marshalDocument(input.Env, format)
+	})
+}
+
+func marshalDocument(data interface{}, format string) (string, error) {
+	if format == "yaml" {
+		var sb strings.Builder
+		encoder := yaml.NewEncoder(&sb)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(data); err != nil {
+			return "", fmt.Errorf("failed to render yaml: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return "", fmt.Errorf("failed to render yaml: %w", err)
+		}
+
+		return sb.String(), nil
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render json: %w", err)
+	}
+
+	return string(content) + "\n", nil
+}
@@ -0,0 +1,51 @@
+package normalizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterFormatter(t *testing.T) {
+	RegisterFormatter("test-keys", FormatterFunc(func(input FormatInput) (string, error) {
+		keys, _ := nciutil.SortedEnvKeys(input.Env)
+		return strings.Join(keys, ","), nil
+	}))
+	defer func() {
+		formattersMu.Lock()
+		delete(formatters, "test-keys")
+		formattersMu.Unlock()
+	}()
+
+	assert.Contains(t, GetFormats(), "test-keys")
+	content, err := FormatEnvironment(map[string]string{"NCI_B": "b", "NCI_A": "a"}, "test-keys")
+	assert.NoError(t, err)
+	assert.Equal(t, "NCI_A,NCI_B", content)
+}
+
+func TestGetFormats(t *testing.T) {
+	assert.Equal(t, []string{"cmd", "cmd-batch", "csh", "docker-env", "dotenv", "export", "fish", "gitlab-dotenv", "gradle-properties", "helm-values", "java-properties", "json", "k8s-configmap", "maven-args", "npm-config", "nushell", "powershell", "yaml"}, GetFormats())
+}
+
+func TestDefaultFormat(t *testing.T) {
+	tests := []struct {
+		goos   string
+		shell  string
+		format string
+	}{
+		{"linux", "/bin/bash", "export"},
+		{"darwin", "/bin/zsh", "export"},
+		{"darwin", "", "export"},
+		{"linux", "/usr/bin/fish", "fish"},
+		{"linux", "/usr/local/bin/nu", "nushell"},
+		{"freebsd", "/bin/tcsh", "csh"},
+		{"windows", "", "powershell"},
+		{"windows", `C:\Program Files\Git\usr\bin\bash.exe`, "export"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.format, defaultFormat(test.goos, test.shell), test.goos+" "+test.shell)
+	}
+}
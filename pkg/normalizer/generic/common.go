@@ -0,0 +1,49 @@
+package generic
+
+// Normalizer is the implementation of the normalizer
+type Normalizer struct {
+	version string
+	name    string
+	slug    string
+}
+
+// GetName returns the name of the normalizer
+func (n Normalizer) GetName() string {
+	return n.name
+}
+
+// GetSlug returns the slug of the normalizer
+func (n Normalizer) GetSlug() string {
+	return n.slug
+}
+
+// GetVersion returns the version of the normalizer
+func (n Normalizer) GetVersion() string {
+	return n.version
+}
+
+// SupportsDenormalize returns true if the normalizer can generate the native environment from the common format
+func (n Normalizer) SupportsDenormalize() bool {
+	return true
+}
+
+// Check if this package can handle the current environment, the generic conventions are set by many systems and therefore only used when requested explicitly
+func (n Normalizer) Check(env map[string]string) bool {
+	return false
+}
+
+// DescribeCheck explains that the generic conventions are never detected
+func (n Normalizer) DescribeCheck(env map[string]string) string {
+	return "never detected, only used if requested explicitly (e.g. denormalize --target generic)"
+}
+
+// NewNormalizer gets an instance of the normalizer
+func NewNormalizer() Normalizer {
+	entity := Normalizer{
+		version: "0.1.0",
+		name:    "Generic CI Conventions",
+		slug:    "generic",
+	}
+
+	return entity
+}
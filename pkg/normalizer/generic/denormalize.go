@@ -0,0 +1,57 @@
+package generic
+
+import (
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+)
+
+// Denormalize generates the well-known cross-vendor variables (Jenkins-style and CI_COMMIT_* aliases) read by many third-party tools
+func (n Normalizer) Denormalize(spec v1.Spec) (map[string]string, error) {
+	data := make(map[string]string)
+
+	// common
+	data["CI"] = "true"
+	data["CI_NAME"] = spec.ServiceSlug
+
+	// pipeline
+	data["BUILD_ID"] = spec.Pipeline.Id
+	data["BUILD_NUMBER"] = spec.Pipeline.Id
+	data["BUILD_URL"] = spec.Pipeline.Url
+	data["JOB_NAME"] = spec.Pipeline.JobName
+	data["CI_PIPELINE_ID"] = spec.Pipeline.Id
+	data["CI_PIPELINE_URL"] = spec.Pipeline.Url
+	data["CI_JOB_NAME"] = spec.Pipeline.JobName
+
+	// repository and project
+	data["GIT_URL"] = spec.Repository.Remote
+	data["CI_REPO"] = spec.Project.Path
+	data["CI_REPO_URL"] = spec.Project.Url
+
+	// commit
+	data["GIT_COMMIT"] = spec.Commit.Hash
+	data["CI_COMMIT_SHA"] = spec.Commit.Hash
+	data["CI_COMMIT_SHORT_SHA"] = spec.Commit.HashShort
+	data["CI_COMMIT_REF_NAME"] = spec.Commit.RefName
+	data["CI_COMMIT_REF_SLUG"] = spec.Commit.RefSlug
+	data["CI_COMMIT_MESSAGE"] = spec.Commit.Title
+	if spec.Commit.RefType == "tag" {
+		data["TAG_NAME"] = spec.Commit.RefName
+		data["GIT_TAG"] = spec.Commit.RefName
+		data["CI_COMMIT_TAG"] = spec.Commit.RefName
+	} else {
+		data["GIT_BRANCH"] = spec.Commit.RefName
+		data["BRANCH_NAME"] = spec.Commit.RefName
+		data["CI_COMMIT_BRANCH"] = spec.Commit.RefName
+	}
+
+	// merge request, Jenkins multibranch pipelines use PR-<id> as branch name
+	if spec.MergeRequest.Id != "" {
+		data["BRANCH_NAME"] = "PR-" + spec.MergeRequest.Id
+		data["CHANGE_ID"] = spec.MergeRequest.Id
+		data["CHANGE_TITLE"] = spec.MergeRequest.Title
+		data["CHANGE_BRANCH"] = spec.MergeRequest.SourceBranchName
+		data["CHANGE_TARGET"] = spec.MergeRequest.TargetBranchName
+		data["CI_COMMIT_PULL_REQUEST"] = spec.MergeRequest.Id
+	}
+
+	return data, nil
+}
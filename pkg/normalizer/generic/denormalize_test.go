@@ -0,0 +1,134 @@
+package generic
+
+import (
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSpec = v1.Spec{
+	ServiceSlug: "github-actions",
+	Pipeline: v1.Pipeline{
+		Id:      "2303126757",
+		Trigger: common.PipelineTriggerPush,
+		JobName: "build",
+		JobSlug: "build",
+		Url:     "https://github.com/cidverse/normalizeci/actions/runs/2303126757",
+	},
+	Repository: v1.Repository{
+		Remote: "https://github.com/cidverse/normalizeci.git",
+	},
+	Project: v1.Project{
+		Path: "cidverse/normalizeci",
+		Url:  "https://github.com/cidverse/normalizeci",
+	},
+	Commit: v1.Commit{
+		RefType:    "branch",
+		RefName:    "feat/generic",
+		RefPath:    "branch/feat/generic",
+		RefSlug:    "feat-generic",
+		RefVCS:     "refs/heads/feat/generic",
+		RefRelease: "feat-generic",
+		Hash:       "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+		HashShort:  "790efd9b",
+		Title:      "feat: add generic target",
+	},
+}
+
+func TestNormalizer_Check(t *testing.T) {
+	var normalizer = NewNormalizer()
+	assert.False(t, normalizer.Check(map[string]string{"CI": "true", "BUILD_NUMBER": "1"}))
+}
+
+func TestNormalizer_Denormalize(t *testing.T) {
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(testSpec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "true", denormalized["CI"])
+	assert.Equal(t, "github-actions", denormalized["CI_NAME"])
+	assert.Equal(t, "2303126757", denormalized["BUILD_NUMBER"])
+	assert.Equal(t, "https://github.com/cidverse/normalizeci/actions/runs/2303126757", denormalized["BUILD_URL"])
+	assert.Equal(t, "790efd9b96e59d9b3c3f1899284c85fa91efbcbc", denormalized["GIT_COMMIT"])
+	assert.Equal(t, "790efd9b96e59d9b3c3f1899284c85fa91efbcbc", denormalized["CI_COMMIT_SHA"])
+	assert.Equal(t, "feat/generic", denormalized["GIT_BRANCH"])
+	assert.Equal(t, "feat/generic", denormalized["BRANCH_NAME"])
+	assert.NotContains(t, denormalized, "TAG_NAME")
+	assert.NotContains(t, denormalized, "CHANGE_ID")
+}
+
+func TestNormalizer_Denormalize_MergeRequest(t *testing.T) {
+	spec := testSpec
+	spec.MergeRequest = v1.MergeRequest{Id: "42", Title: "feat: add generic target", SourceBranchName: "feat/generic", TargetBranchName: "main"}
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PR-42", denormalized["BRANCH_NAME"])
+	assert.Equal(t, "42", denormalized["CHANGE_ID"])
+	assert.Equal(t, "feat/generic", denormalized["CHANGE_BRANCH"])
+	assert.Equal(t, "main", denormalized["CHANGE_TARGET"])
+}
+
+func TestNormalizer_Denormalize_Tag(t *testing.T) {
+	spec := testSpec
+	spec.Commit.RefType = "tag"
+	spec.Commit.RefName = "v1.0.0"
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", denormalized["TAG_NAME"])
+	assert.Equal(t, "v1.0.0", denormalized["CI_COMMIT_TAG"])
+	assert.NotContains(t, denormalized, "GIT_BRANCH")
+}
+
+func TestNormalizer_Normalize_RoundTrip(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(testSpec)
+	assert.NoError(t, err)
+	var normalized, normalizeErr = normalizer.Normalize(denormalized)
+
+	assert.NoError(t, normalizeErr)
+	assert.Equal(t, "generic", normalized.ServiceSlug)
+	assert.Equal(t, testSpec.Pipeline.Id, normalized.Pipeline.Id)
+	assert.Equal(t, testSpec.Pipeline.Url, normalized.Pipeline.Url)
+	assert.Equal(t, testSpec.Pipeline.JobName, normalized.Pipeline.JobName)
+	assert.Equal(t, testSpec.Pipeline.JobSlug, normalized.Pipeline.JobSlug)
+	assert.Equal(t, testSpec.Commit.RefType, normalized.Commit.RefType)
+	assert.Equal(t, testSpec.Commit.RefName, normalized.Commit.RefName)
+	assert.Equal(t, testSpec.Commit.RefPath, normalized.Commit.RefPath)
+	assert.Equal(t, testSpec.Commit.RefSlug, normalized.Commit.RefSlug)
+	assert.Equal(t, testSpec.Commit.RefVCS, normalized.Commit.RefVCS)
+	assert.Equal(t, testSpec.Commit.RefRelease, normalized.Commit.RefRelease)
+	assert.Equal(t, testSpec.Commit.Hash, normalized.Commit.Hash)
+	assert.Equal(t, testSpec.Commit.HashShort, normalized.Commit.HashShort)
+	assert.Equal(t, testSpec.Commit.Title, normalized.Commit.Title)
+}
+
+func TestNormalizer_Normalize_Jenkins(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	var normalizer = NewNormalizer()
+	var normalized, err = normalizer.Normalize(map[string]string{
+		"BUILD_ID":      "17",
+		"GIT_BRANCH":    "origin/main",
+		"CHANGE_ID":     "5",
+		"CHANGE_BRANCH": "feat/generic",
+		"CHANGE_TARGET": "main",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "17", normalized.Pipeline.Id)
+	assert.Equal(t, common.PipelineTriggerMergeRequest, normalized.Pipeline.Trigger)
+	assert.Equal(t, "main", normalized.Commit.RefName)
+	assert.Equal(t, "5", normalized.MergeRequest.Id)
+	assert.Equal(t, "feat/generic", normalized.MergeRequest.SourceBranchName)
+}
@@ -0,0 +1,84 @@
+package generic
+
+import (
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/normalizer/localgit"
+	"github.com/cidverse/normalizeci/pkg/vcsrepository"
+	"github.com/gosimple/slug"
+)
+
+// Normalize normalizes the environment variables into the common format, values not covered by the conventions are taken from the local git repository
+func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
+	nci, _, err := n.NormalizeWithProvenance(env)
+	return nci, err
+}
+
+// NormalizeWithProvenance normalizes the environment variables into the common format and records the source of each field
+func (n Normalizer) NormalizeWithProvenance(env map[string]string) (v1.Spec, v1.Provenance, error) {
+	nci, prov, err := localgit.NewNormalizer().NormalizeWithProvenance(env)
+	if err != nil {
+		return nci, prov, err
+	}
+	nci.ServiceName = n.name
+	nci.ServiceSlug = n.slug
+
+	// pipeline
+	nci.Pipeline.Id = prov.First("pipeline.id", v1.EnvCandidate(env, "BUILD_ID"), v1.EnvCandidate(env, "CI_PIPELINE_ID"), v1.SourceCandidate(nci.Pipeline.Id, v1.SourceDefault, ""))
+	nci.Pipeline.Url = prov.First("pipeline.url", v1.EnvCandidate(env, "BUILD_URL"), v1.EnvCandidate(env, "CI_PIPELINE_URL"))
+	if jobName := prov.First("pipeline.jobName", v1.EnvCandidate(env, "JOB_NAME"), v1.EnvCandidate(env, "CI_JOB_NAME"), v1.SourceCandidate(nci.Pipeline.JobName, v1.SourceDefault, "")); jobName != nci.Pipeline.JobName {
+		nci.Pipeline.JobName = jobName
+		nci.Pipeline.JobSlug = slug.Make(jobName)
+		prov["pipeline.jobSlug"] = prov["pipeline.jobName"]
+	}
+
+	// commit
+	nci.Commit.Hash = prov.First("commit.hash", v1.EnvCandidate(env, "GIT_COMMIT"), v1.EnvCandidate(env, "CI_COMMIT_SHA"), v1.SourceCandidate(nci.Commit.Hash, v1.SourceGit, "HEAD"))
+	nci.Commit.HashShort = prov.First("commit.hashShort", v1.EnvCandidate(env, "CI_COMMIT_SHORT_SHA"), v1.SourceCandidate(nci.Commit.HashShort, v1.SourceGit, "HEAD"))
+	nci.Commit.Title = prov.First("commit.title", v1.EnvCandidate(env, "CI_COMMIT_MESSAGE"), v1.SourceCandidate(nci.Commit.Title, v1.SourceGit, "HEAD"))
+	if tag, key := firstEnv(env, "TAG_NAME", "GIT_TAG", "CI_COMMIT_TAG"); tag != "" {
+		setRef(&nci, prov, key, "tag", tag, "refs/tags/")
+	} else if branch, key := firstEnv(env, "GIT_BRANCH", "CI_COMMIT_BRANCH"); branch != "" {
+		// the jenkins git plugin prefixes the branch with the remote name
+		setRef(&nci, prov, key, "branch", strings.TrimPrefix(branch, "origin/"), "refs/heads/")
+	}
+
+	// merge request
+	if changeId, key := firstEnv(env, "CHANGE_ID", "CI_COMMIT_PULL_REQUEST"); changeId != "" {
+		nci.Pipeline.Trigger = common.PipelineTriggerMergeRequest
+		nci.MergeRequest.Id = changeId
+		nci.MergeRequest.Title = prov.Env("mergeRequest.title", env, "CHANGE_TITLE")
+		nci.MergeRequest.SourceBranchName = prov.Env("mergeRequest.sourceBranchName", env, "CHANGE_BRANCH")
+		nci.MergeRequest.TargetBranchName = prov.Env("mergeRequest.targetBranchName", env, "CHANGE_TARGET")
+		prov.Set("pipeline.trigger", v1.SourceEnv, key)
+		prov.Set("mergeRequest.id", v1.SourceEnv, key)
+	}
+
+	prov.Complete(nci)
+	return nci, prov, nil
+}
+
+// firstEnv returns the first non-empty value of the env variables and its name
+func firstEnv(env map[string]string, keys ...string) (string, string) {
+	for _, key := range keys {
+		if value := env[key]; value != "" {
+			return value, key
+		}
+	}
+
+	return "", ""
+}
+
+func setRef(nci *v1.Spec, prov v1.Provenance, key string, refType string, refName string, vcsPrefix string) {
+	nci.Commit.RefType = refType
+	nci.Commit.RefName = refName
+	nci.Commit.RefPath = refType + "/" + refName
+	nci.Commit.RefSlug = slug.Make(refName)
+	nci.Commit.RefVCS = vcsPrefix + refName
+	nci.Commit.RefRelease = vcsrepository.ToReleaseName(refName)
+	for _, field := range []string{"commit.refType", "commit.refName", "commit.refPath", "commit.refSlug", "commit.refVcs", "commit.refRelease"} {
+		prov.Set(field, v1.SourceEnv, key)
+	}
+}
@@ -1,9 +1,119 @@
 package githubactions
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
 	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
 )
 
+// Denormalize generates the GitHub Actions default environment variables from the common format
 func (n Normalizer) Denormalize(spec v1.Spec) (map[string]string, error) {
-	return make(map[string]string), nil
+	data := make(map[string]string)
+
+	// common
+	data["CI"] = "true"
+	data["GITHUB_ACTIONS"] = "true"
+
+	// worker
+	runnerOS, runnerArch := nciutil.SplitArch(spec.Worker.Arch)
+	data["RUNNER_TRACKING_ID"] = spec.Worker.Id
+	data["RUNNER_NAME"] = spec.Worker.Name
+	data["RUNNER_OS"] = githubRunnerOS(runnerOS)
+	data["RUNNER_ARCH"] = nciutil.ToRunnerArch(runnerArch)
+	imageParts := strings.SplitN(spec.Worker.OS, ":", 2)
+	data["ImageOS"] = imageParts[0]
+	if len(imageParts) == 2 {
+		data["ImageVersion"] = imageParts[1]
+	}
+
+	// pipeline
+	data["GITHUB_RUN_ID"] = spec.Pipeline.Id
+	data["GITHUB_RUN_ATTEMPT"] = spec.Pipeline.Attempt
+	data["GITHUB_EVENT_NAME"] = githubEventName(spec.Pipeline.Trigger)
+	data["GITHUB_WORKFLOW"] = spec.Pipeline.StageName
+	data["GITHUB_JOB"] = spec.Pipeline.JobSlug
+	data["GITHUB_ACTION"] = spec.Pipeline.JobName
+	if spec.Pipeline.ConfigFile != "" {
+		data["GITHUB_WORKFLOW_REF"] = fmt.Sprintf("%s/%s@%s", spec.Project.Path, spec.Pipeline.ConfigFile, spec.Commit.RefVCS)
+	}
+
+	// repository and project
+	data["GITHUB_SERVER_URL"] = githubServerURL(spec)
+	data["GITHUB_API_URL"] = githubAPIURL(data["GITHUB_SERVER_URL"])
+	data["GITHUB_REPOSITORY"] = spec.Project.Path
+	data["GITHUB_REPOSITORY_ID"] = spec.Project.Id
+	data["GITHUB_REPOSITORY_OWNER"] = strings.SplitN(spec.Project.Path, "/", 2)[0]
+	data["GITHUB_WORKSPACE"] = spec.Project.Dir
+
+	// commit
+	data["GITHUB_SHA"] = spec.Commit.Hash
+	data["GITHUB_REF"] = spec.Commit.RefVCS
+	data["GITHUB_REF_NAME"] = spec.Commit.RefName
+	data["GITHUB_REF_TYPE"] = spec.Commit.RefType
+	data["GITHUB_ACTOR"] = spec.Commit.AuthorName
+
+	// pull request, GitHub runs pull request workflows on the merge ref
+	if spec.Pipeline.Trigger == common.PipelineTriggerMergeRequest && spec.MergeRequest.Id != "" {
+		data["GITHUB_REF"] = fmt.Sprintf("refs/pull/%s/merge", spec.MergeRequest.Id)
+		data["GITHUB_REF_NAME"] = fmt.Sprintf("%s/merge", spec.MergeRequest.Id)
+		data["GITHUB_REF_TYPE"] = "branch"
+		data["GITHUB_HEAD_REF"] = spec.MergeRequest.SourceBranchName
+		data["GITHUB_BASE_REF"] = spec.MergeRequest.TargetBranchName
+	}
+
+	return data, nil
+}
+
+// githubEventName maps the normalized pipeline trigger to the GitHub event name
+func githubEventName(trigger string) string {
+	switch trigger {
+	case common.PipelineTriggerMergeRequest:
+		return "pull_request"
+	case common.PipelineTriggerSchedule:
+		return "schedule"
+	case common.PipelineTriggerManual, common.PipelineTriggerCLI:
+		return "workflow_dispatch"
+	case common.PipelineTriggerBuild:
+		return "workflow_run"
+	default:
+		return "push"
+	}
+}
+
+// githubRunnerOS maps the go operating system name to the value GitHub uses for RUNNER_OS
+func githubRunnerOS(goos string) string {
+	switch goos {
+	case "linux":
+		return "Linux"
+	case "windows":
+		return "Windows"
+	case "darwin":
+		return "macOS"
+	default:
+		return goos
+	}
+}
+
+// githubServerURL returns the server url, preferring the project url over the repository host
+func githubServerURL(spec v1.Spec) string {
+	if spec.Project.Url != "" && spec.Project.Path != "" && strings.HasSuffix(spec.Project.Url, "/"+spec.Project.Path) {
+		return strings.TrimSuffix(spec.Project.Url, "/"+spec.Project.Path)
+	}
+	if spec.Repository.HostServer != "" {
+		return "https://" + spec.Repository.HostServer
+	}
+
+	return "https://github.com"
+}
+
+// githubAPIURL returns the api url for github.com or a GitHub Enterprise Server instance
+func githubAPIURL(serverURL string) string {
+	if serverURL == "https://github.com" {
+		return "https://api.github.com"
+	}
+
+	return serverURL + "/api/v3"
 }
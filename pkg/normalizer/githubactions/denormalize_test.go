@@ -0,0 +1,175 @@
+package githubactions
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/google/go-github/v69/github"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSpec = v1.Spec{
+	Worker: v1.Worker{
+		Id:   "github_969396af-1899-4849-9318-7807141c54e9",
+		Name: "GitHub Actions 2",
+		OS:   "ubuntu22:20240101.1",
+		Arch: "linux/amd64",
+	},
+	Pipeline: v1.Pipeline{
+		Id:         "2303126757",
+		Trigger:    common.PipelineTriggerPush,
+		StageName:  "ci",
+		JobName:    "build",
+		JobSlug:    "build",
+		Attempt:    "2",
+		ConfigFile: ".github/workflows/ci.yml",
+	},
+	Repository: v1.Repository{
+		HostServer: "github.com",
+	},
+	Project: v1.Project{
+		Id:   "205438004",
+		Name: "normalizeci",
+		Path: "cidverse/normalizeci",
+		Url:  "https://github.com/cidverse/normalizeci",
+		Dir:  "/home/runner/work/normalizeci/normalizeci",
+	},
+	Commit: v1.Commit{
+		RefType:    "branch",
+		RefName:    "main",
+		RefVCS:     "refs/heads/main",
+		Hash:       "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+		AuthorName: "octocat",
+		Title:      "feat: add denormalize",
+	},
+}
+
+func TestNormalizer_Denormalize(t *testing.T) {
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(testSpec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "true", denormalized["GITHUB_ACTIONS"])
+	assert.Equal(t, "790efd9b96e59d9b3c3f1899284c85fa91efbcbc", denormalized["GITHUB_SHA"])
+	assert.Equal(t, "refs/heads/main", denormalized["GITHUB_REF"])
+	assert.Equal(t, "main", denormalized["GITHUB_REF_NAME"])
+	assert.Equal(t, "branch", denormalized["GITHUB_REF_TYPE"])
+	assert.Equal(t, "cidverse/normalizeci", denormalized["GITHUB_REPOSITORY"])
+	assert.Equal(t, "cidverse", denormalized["GITHUB_REPOSITORY_OWNER"])
+	assert.Equal(t, "https://github.com", denormalized["GITHUB_SERVER_URL"])
+	assert.Equal(t, "https://api.github.com", denormalized["GITHUB_API_URL"])
+	assert.Equal(t, "2303126757", denormalized["GITHUB_RUN_ID"])
+	assert.Equal(t, "2", denormalized["GITHUB_RUN_ATTEMPT"])
+	assert.Equal(t, "ci", denormalized["GITHUB_WORKFLOW"])
+	assert.Equal(t, "cidverse/normalizeci/.github/workflows/ci.yml@refs/heads/main", denormalized["GITHUB_WORKFLOW_REF"])
+	assert.Equal(t, "build", denormalized["GITHUB_JOB"])
+	assert.Equal(t, "push", denormalized["GITHUB_EVENT_NAME"])
+	assert.Equal(t, "Linux", denormalized["RUNNER_OS"])
+	assert.Equal(t, "X64", denormalized["RUNNER_ARCH"])
+	assert.Equal(t, "ubuntu22", denormalized["ImageOS"])
+	assert.Equal(t, "20240101.1", denormalized["ImageVersion"])
+	assert.NotContains(t, denormalized, "GITHUB_HEAD_REF")
+}
+
+func TestNormalizer_Denormalize_PullRequest(t *testing.T) {
+	spec := testSpec
+	spec.Pipeline.Trigger = common.PipelineTriggerMergeRequest
+	spec.MergeRequest = v1.MergeRequest{
+		Id:               "42",
+		SourceBranchName: "feat/denormalize",
+		TargetBranchName: "main",
+	}
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pull_request", denormalized["GITHUB_EVENT_NAME"])
+	assert.Equal(t, "refs/pull/42/merge", denormalized["GITHUB_REF"])
+	assert.Equal(t, "42/merge", denormalized["GITHUB_REF_NAME"])
+	assert.Equal(t, "feat/denormalize", denormalized["GITHUB_HEAD_REF"])
+	assert.Equal(t, "main", denormalized["GITHUB_BASE_REF"])
+}
+
+func TestNormalizer_Denormalize_EnterpriseServer(t *testing.T) {
+	spec := testSpec
+	spec.Project.Url = "https://github.example.com/cidverse/normalizeci"
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.example.com", denormalized["GITHUB_SERVER_URL"])
+	assert.Equal(t, "https://github.example.com/api/v3", denormalized["GITHUB_API_URL"])
+}
+
+func TestNormalizer_Denormalize_RoundTrip(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	githubMockClient = &http.Client{}
+	httpmock.ActivateNonDefault(githubMockClient)
+	defer httpmock.DeactivateAndReset()
+
+	spec := testSpec
+	spec.Pipeline.Trigger = common.PipelineTriggerMergeRequest
+	spec.MergeRequest = v1.MergeRequest{Id: "42", SourceBranchName: "feat/denormalize", TargetBranchName: "main"}
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(spec)
+	assert.NoError(t, err)
+	var normalized, normalizeErr = normalizer.Normalize(denormalized)
+
+	assert.NoError(t, normalizeErr)
+	assert.True(t, normalizer.Check(denormalized))
+	assert.Equal(t, spec.Worker.Id, normalized.Worker.Id)
+	assert.Equal(t, spec.Worker.OS, normalized.Worker.OS)
+	assert.Equal(t, spec.Pipeline.Id, normalized.Pipeline.Id)
+	assert.Equal(t, spec.Pipeline.Trigger, normalized.Pipeline.Trigger)
+	assert.Equal(t, spec.Pipeline.StageName, normalized.Pipeline.StageName)
+	assert.Equal(t, spec.Pipeline.JobName, normalized.Pipeline.JobName)
+	assert.Equal(t, spec.Pipeline.Attempt, normalized.Pipeline.Attempt)
+	assert.Equal(t, spec.Project.Url, normalized.Project.Url)
+	assert.Equal(t, spec.Pipeline.ConfigFile, normalized.Pipeline.ConfigFile)
+	assert.Equal(t, spec.Worker.Name, normalized.Worker.Name)
+	assert.Equal(t, spec.MergeRequest.Id, normalized.MergeRequest.Id)
+	assert.Equal(t, spec.MergeRequest.SourceBranchName, normalized.MergeRequest.SourceBranchName)
+	assert.Equal(t, spec.MergeRequest.TargetBranchName, normalized.MergeRequest.TargetBranchName)
+}
+
+func TestWriteGithubEvent(t *testing.T) {
+	spec := testSpec
+	spec.Pipeline.Trigger = common.PipelineTriggerMergeRequest
+	spec.MergeRequest = v1.MergeRequest{
+		Id:               "42",
+		Title:            "feat: add denormalize",
+		SourceBranchName: "feat/denormalize",
+		SourceHash:       "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+		TargetBranchName: "main",
+		TargetHash:       "4a9b2f1c8e0d7a6b5c4d3e2f1a0b9c8d7e6f5a4b",
+	}
+
+	vars, err := WriteGithubEvent(spec, t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, "pull_request", vars["GITHUB_EVENT_NAME"])
+
+	event, err := ParseGithubEvent(vars["GITHUB_EVENT_NAME"], vars["GITHUB_EVENT_PATH"])
+	assert.NoError(t, err)
+	pullRequestEvent, ok := event.(*github.PullRequestEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 42, pullRequestEvent.PullRequest.GetNumber())
+	assert.Equal(t, "feat: add denormalize", pullRequestEvent.PullRequest.GetTitle())
+	assert.Equal(t, "feat/denormalize", pullRequestEvent.PullRequest.Head.GetRef())
+	assert.Equal(t, "main", pullRequestEvent.PullRequest.Base.GetRef())
+}
+
+func TestWriteGithubEvent_InvalidMergeRequestId(t *testing.T) {
+	spec := testSpec
+	spec.Pipeline.Trigger = common.PipelineTriggerMergeRequest
+
+	_, err := WriteGithubEvent(spec, t.TempDir())
+	assert.Error(t, err)
+}
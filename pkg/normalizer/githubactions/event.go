@@ -0,0 +1,117 @@
+package githubactions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/google/go-github/v69/github"
+)
+
+// CreateGithubEvent generates a synthetic event payload matching the GITHUB_EVENT_NAME generated by Denormalize
+//
+// Parameters:
+//   - spec: the normalized spec
+//
+// Returns:
+//   - string: the GitHub event name
+//   - []byte: the event payload as json
+//   - error: An error value, if any.
+func CreateGithubEvent(spec v1.Spec) (string, []byte, error) {
+	eventName := githubEventName(spec.Pipeline.Trigger)
+	repository := &github.Repository{
+		Name:          github.Ptr(spec.Project.Name),
+		FullName:      github.Ptr(spec.Project.Path),
+		HTMLURL:       github.Ptr(spec.Project.Url),
+		DefaultBranch: github.Ptr(spec.Project.DefaultBranch),
+	}
+
+	var payload interface{}
+	switch eventName {
+	case "pull_request":
+		number, err := strconv.Atoi(spec.MergeRequest.Id)
+		if err != nil {
+			return eventName, nil, fmt.Errorf("invalid merge request id %q: %w", spec.MergeRequest.Id, err)
+		}
+
+		payload = github.PullRequestEvent{
+			Action: github.Ptr("synchronize"),
+			Number: github.Ptr(number),
+			PullRequest: &github.PullRequest{
+				Number: github.Ptr(number),
+				Title:  github.Ptr(spec.MergeRequest.Title),
+				Head: &github.PullRequestBranch{
+					Ref: github.Ptr(spec.MergeRequest.SourceBranchName),
+					SHA: github.Ptr(spec.MergeRequest.SourceHash),
+				},
+				Base: &github.PullRequestBranch{
+					Ref: github.Ptr(spec.MergeRequest.TargetBranchName),
+					SHA: github.Ptr(spec.MergeRequest.TargetHash),
+				},
+			},
+			Repo: repository,
+		}
+	case "workflow_dispatch":
+		inputs, err := json.Marshal(spec.Pipeline.Input)
+		if err != nil {
+			return eventName, nil, fmt.Errorf("failed to encode inputs: %w", err)
+		}
+
+		payload = github.WorkflowDispatchEvent{
+			Ref:      github.Ptr(spec.Commit.RefVCS),
+			Workflow: github.Ptr(spec.Pipeline.ConfigFile),
+			Inputs:   inputs,
+			Repo:     repository,
+		}
+	default:
+		payload = github.PushEvent{
+			Ref:   github.Ptr(spec.Commit.RefVCS),
+			After: github.Ptr(spec.Commit.Hash),
+			HeadCommit: &github.HeadCommit{
+				ID:        github.Ptr(spec.Commit.Hash),
+				Message:   github.Ptr(spec.Commit.Title),
+				Author:    &github.CommitAuthor{Name: github.Ptr(spec.Commit.AuthorName), Email: github.Ptr(spec.Commit.AuthorEmail)},
+				Committer: &github.CommitAuthor{Name: github.Ptr(spec.Commit.CommitterName), Email: github.Ptr(spec.Commit.CommitterEmail)},
+			},
+			Repo: &github.PushEventRepository{
+				Name:          github.Ptr(spec.Project.Name),
+				FullName:      github.Ptr(spec.Project.Path),
+				HTMLURL:       github.Ptr(spec.Project.Url),
+				DefaultBranch: github.Ptr(spec.Project.DefaultBranch),
+			},
+		}
+	}
+
+	content, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return eventName, nil, fmt.Errorf("failed to encode event payload: %w", err)
+	}
+
+	return eventName, content, nil
+}
+
+// WriteGithubEvent writes a synthetic event payload into dir and returns the variables pointing at it
+//
+// Returns:
+//   - map[string]string: GITHUB_EVENT_NAME and GITHUB_EVENT_PATH
+//   - error: An error value, if any.
+func WriteGithubEvent(spec v1.Spec, dir string) (map[string]string, error) {
+	eventName, content, err := CreateGithubEvent(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	eventFile := filepath.Join(dir, "github_event.json")
+	err = os.WriteFile(eventFile, content, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write GITHUB_EVENT_PATH file: %w", err)
+	}
+
+	return map[string]string{
+		"GITHUB_EVENT_NAME": eventName,
+		"GITHUB_EVENT_PATH": eventFile,
+	}, nil
+}
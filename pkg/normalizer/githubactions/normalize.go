@@ -3,7 +3,6 @@ package githubactions
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"runtime"
 	"strings"
 	"time"
@@ -21,64 +20,79 @@ import (
 
 // Normalize normalizes the environment variables into the common format
 func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
+	nci, _, err := n.NormalizeWithProvenance(env)
+	return nci, err
+}
+
+// NormalizeWithProvenance normalizes the environment variables into the common format and records the source of each field
+func (n Normalizer) NormalizeWithProvenance(env map[string]string) (v1.Spec, v1.Provenance, error) {
 	nci := v1.Create(n.name, n.slug)
+	prov := v1.Provenance{}
 
 	// worker
 	nci.Worker = v1.Worker{
-		Id:      env["RUNNER_TRACKING_ID"],
-		Name:    env["RUNNER_TRACKING_ID"],
+		Id:      prov.Env("worker.id", env, "RUNNER_TRACKING_ID"),
+		Name:    prov.First("worker.name", v1.EnvCandidate(env, "RUNNER_NAME"), v1.EnvCandidate(env, "RUNNER_TRACKING_ID")),
 		Type:    "github_hosted_vm",
 		OS:      env["ImageOS"] + ":" + env["ImageVersion"],
 		Version: "latest",
 		Arch:    runtime.GOOS + "/" + runtime.GOARCH,
 	}
 
+	prov.Set("worker.os", v1.SourceEnv, "ImageOS, ImageVersion")
+
 	// pipeline
-	nci.Pipeline.Id = env["GITHUB_RUN_ID"]
-	pipelineEvent := env["GITHUB_EVENT_NAME"]
-	switch pipelineEvent {
-	case "push":
-		nci.Pipeline.Trigger = common.PipelineTriggerPush
-	case "pull_request":
-		nci.Pipeline.Trigger = common.PipelineTriggerMergeRequest
-	default:
-		nci.Pipeline.Trigger = common.PipelineTriggerUnknown
-	}
+	nci.Pipeline.Id = prov.Env("pipeline.id", env, "GITHUB_RUN_ID")
+	nci.Pipeline.Trigger = githubTriggerNormalize(prov.Env("pipeline.trigger", env, "GITHUB_EVENT_NAME"))
 
-	nci.Pipeline.StageName = env["GITHUB_WORKFLOW"]
-	nci.Pipeline.StageSlug = slug.Make(env["GITHUB_WORKFLOW"])
-	nci.Pipeline.JobName = env["GITHUB_ACTION"]
-	nci.Pipeline.JobSlug = slug.Make(env["GITHUB_ACTION"])
+	nci.Pipeline.StageName = prov.Env("pipeline.stageName", env, "GITHUB_WORKFLOW")
+	nci.Pipeline.StageSlug = slug.Make(prov.Env("pipeline.stageSlug", env, "GITHUB_WORKFLOW"))
+	nci.Pipeline.JobName = prov.Env("pipeline.jobName", env, "GITHUB_ACTION")
+	nci.Pipeline.JobSlug = slug.Make(prov.Env("pipeline.jobSlug", env, "GITHUB_ACTION"))
 	nci.Pipeline.JobStartedAt = time.Now().UTC().Format(time.RFC3339)
-	nci.Pipeline.Attempt = env["GITHUB_RUN_ATTEMPT"]
+	nci.Pipeline.Attempt = prov.Env("pipeline.attempt", env, "GITHUB_RUN_ATTEMPT")
 	nci.Pipeline.Url = fmt.Sprintf("%s/%s/actions/runs/%s", env["GITHUB_SERVER_URL"], env["GITHUB_REPOSITORY"], env["GITHUB_RUN_ID"])
+	prov.Set("pipeline.url", v1.SourceEnv, "GITHUB_SERVER_URL, GITHUB_REPOSITORY, GITHUB_RUN_ID")
+	// GITHUB_WORKFLOW_REF contains the workflow file in the format owner/repo/.github/workflows/ci.yml@refs/heads/main
+	if configFile, _, found := strings.Cut(strings.TrimPrefix(env["GITHUB_WORKFLOW_REF"], env["GITHUB_REPOSITORY"]+"/"), "@"); found {
+		nci.Pipeline.ConfigFile = configFile
+		prov.Set("pipeline.configFile", v1.SourceEnv, "GITHUB_WORKFLOW_REF")
+	}
 
 	// pull request (fallback in case there are issues with the event json)
 	if nci.Pipeline.Trigger == common.PipelineTriggerMergeRequest {
 		splitRef := strings.Split(env["GITHUB_REF"], "/")
 		nci.MergeRequest.Id = splitRef[2]
+		prov.Set("mergeRequest.id", v1.SourceEnv, "GITHUB_REF")
+		nci.MergeRequest.SourceBranchName = prov.Env("mergeRequest.sourceBranchName", env, "GITHUB_HEAD_REF")
+		nci.MergeRequest.TargetBranchName = prov.Env("mergeRequest.targetBranchName", env, "GITHUB_BASE_REF")
 	}
 
 	// repository
 	projectDir, err := vcsutil.FindProjectDirectoryFromWorkDir()
 	if err != nil {
-		return nci, fmt.Errorf("failed to find project directory: %v", err)
+		return nci, prov, fmt.Errorf("failed to find project directory: %v", err)
 	}
 	vcsData, err := vcsrepository.GetVCSRepositoryInformation(projectDir)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get repository details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get repository details: %v", err)
 	}
 	nci.Repository = vcsData.Repository
 	nci.Commit = vcsData.Commit
+	prov.SetSection(nci, "repository", v1.SourceGit, "")
+	prov.SetSection(nci, "commit", v1.SourceGit, "HEAD")
 
 	// project
 	projectData, err := projectdetails.GetProjectDetails(nci.Repository.Kind, nci.Repository.Remote, nci.Repository.HostType, nci.Repository.HostServer)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get project details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get project details: %v", err)
 	}
 	nci.Project = projectData
+	prov.SetSection(nci, "project", v1.SourceAPI, nci.Repository.HostType)
 	nci.Project.Url = nciutil.GetValueFromMap(env, "GITHUB_SERVER_URL") + "/" + nciutil.GetValueFromMap(env, "GITHUB_REPOSITORY")
+	prov.Set("project.url", v1.SourceEnv, "GITHUB_SERVER_URL, GITHUB_REPOSITORY")
 	nci.Project.Dir = projectDir
+	prov.Set("project.dir", v1.SourceGit, "")
 
 	// flags
 	nci.Flags.DeployFreeze = "false"
@@ -89,10 +103,12 @@ func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
 		// pipeline
 		nci.Pipeline.JobStartedAt = wfRun.GetRunStartedAt().UTC().Format(time.RFC3339)
 		nci.Pipeline.ConfigFile = wf.GetPath()
+		prov.Set("pipeline.jobStartedAt", v1.SourceAPI, "actions/runs")
+		prov.Set("pipeline.configFile", v1.SourceAPI, "actions/workflows")
 	}
 
 	// parse event context
-	githubEvent, err := ParseGithubEvent(os.Getenv("GITHUB_EVENT_NAME"), os.Getenv("GITHUB_EVENT_PATH"))
+	githubEvent, err := ParseGithubEvent(env["GITHUB_EVENT_NAME"], env["GITHUB_EVENT_PATH"])
 	if err == nil {
 		variables := make(map[string]string)
 
@@ -106,6 +122,12 @@ func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
 				TargetBranchName: pullRequestEvent.PullRequest.Base.GetRef(),
 				TargetHash:       pullRequestEvent.PullRequest.Base.GetSHA(),
 			}
+			prov.Set("mergeRequest.id", v1.SourceEvent, "pull_request.number")
+			prov.Set("mergeRequest.title", v1.SourceEvent, "pull_request.title")
+			prov.Set("mergeRequest.sourceBranchName", v1.SourceEvent, "pull_request.head.ref")
+			prov.Set("mergeRequest.sourceHash", v1.SourceEvent, "pull_request.head.sha")
+			prov.Set("mergeRequest.targetBranchName", v1.SourceEvent, "pull_request.base.ref")
+			prov.Set("mergeRequest.targetHash", v1.SourceEvent, "pull_request.base.sha")
 		}
 
 		// workflow dispatch event can have custom input parameters
@@ -119,6 +141,7 @@ func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
 
 				for key, value := range inputs {
 					variables[key] = fmt.Sprintf("%v", value)
+					prov.Set("pipeline.inputs."+key, v1.SourceEvent, "inputs."+key)
 				}
 			}
 		}
@@ -126,5 +149,23 @@ func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
 		nci.Pipeline.Input = variables
 	}
 
-	return nci, nil
+	prov.Complete(nci)
+	return nci, prov, nil
+}
+
+func githubTriggerNormalize(event string) string {
+	switch event {
+	case "push":
+		return common.PipelineTriggerPush
+	case "pull_request":
+		return common.PipelineTriggerMergeRequest
+	case "schedule":
+		return common.PipelineTriggerSchedule
+	case "workflow_dispatch":
+		return common.PipelineTriggerManual
+	case "workflow_run":
+		return common.PipelineTriggerBuild
+	}
+
+	return common.PipelineTriggerUnknown
 }
@@ -110,3 +110,23 @@ func TestNormalizer_Normalize_WorkflowAPI(t *testing.T) {
 	assert.Equal(t, "2022-05-10T20:20:59Z", normalized.Pipeline.JobStartedAt)
 	assert.Equal(t, ".github/workflows/ci.yml", normalized.Pipeline.ConfigFile)
 }
+
+func TestNormalizer_Normalize_Trigger(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	tests := map[string]string{
+		"push":              "push",
+		"pull_request":      "merge_request",
+		"schedule":          "schedule",
+		"workflow_dispatch": "manual",
+		"workflow_run":      "build",
+		"release":           "unknown",
+	}
+	for event, trigger := range tests {
+		var normalizer = NewNormalizer()
+		var normalized, err = normalizer.Normalize(map[string]string{"GITHUB_EVENT_NAME": event, "GITHUB_REF": "refs/pull/1/merge"})
+
+		assert.NoError(t, err, event)
+		assert.Equal(t, trigger, normalized.Pipeline.Trigger, event)
+	}
+}
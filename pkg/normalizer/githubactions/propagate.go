@@ -0,0 +1,79 @@
+package githubactions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/rs/zerolog/log"
+)
+
+// commandEscaper escapes the data of a workflow command
+var commandEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+)
+
+// Propagate appends the variables to the GITHUB_ENV file and masks secrets using workflow commands
+func (n Normalizer) Propagate(env map[string]string, vars map[string]string, opts api.PropagateOptions) (string, error) {
+	envFile := env["GITHUB_ENV"]
+	if envFile == "" {
+		return "", errors.New("GITHUB_ENV is not set")
+	}
+
+	keys, err := nciutil.SortedEnvKeys(vars)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	var content strings.Builder
+	for _, key := range keys {
+		// default variables can't be overwritten, see https://docs.github.com/en/actions/writing-workflows/choosing-what-your-workflow-does/store-information-in-variables#default-environment-variables
+		if strings.HasPrefix(key, "GITHUB_") || strings.HasPrefix(key, "RUNNER_") {
+			log.Debug().Str("key", key).Msg("skipping default variable, it can't be overwritten in GITHUB_ENV")
+			continue
+		}
+
+		value := vars[key]
+		if opts.IsSecret(key) {
+			for _, line := range strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n") {
+				if line != "" {
+					output.WriteString("::add-mask::" + commandEscaper.Replace(line) + "\n")
+				}
+			}
+		}
+
+		delimiter, err := heredocDelimiter(value)
+		if err != nil {
+			return "", err
+		}
+		content.WriteString(fmt.Sprintf("%s<<%s\n%s\n%s\n", key, delimiter, value, delimiter))
+	}
+
+	if err = nciutil.AppendToFile(envFile, content.String()); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
+
+// heredocDelimiter generates a random delimiter that is not contained in the value
+func heredocDelimiter(value string) (string, error) {
+	for {
+		random := make([]byte, 16)
+		if _, err := rand.Read(random); err != nil {
+			return "", fmt.Errorf("failed to generate heredoc delimiter: %w", err)
+		}
+
+		delimiter := "ghadelimiter_" + hex.EncodeToString(random)
+		if !strings.Contains(value, delimiter) {
+			return delimiter, nil
+		}
+	}
+}
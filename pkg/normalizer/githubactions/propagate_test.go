@@ -0,0 +1,39 @@
+package githubactions
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizer_Propagate(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "github_env")
+	var normalizer = NewNormalizer()
+	output, err := normalizer.Propagate(
+		map[string]string{"GITHUB_ENV": envFile},
+		map[string]string{
+			"NCI_COMMIT_DESCRIPTION": "line 1\nline 2",
+			"NCI_TOKEN":              "secret\nvalue",
+			"GITHUB_SHA":             "ignored",
+		},
+		api.PropagateOptions{Secrets: []string{"NCI_TOKEN"}},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "::add-mask::secret\n::add-mask::value\n", output)
+
+	content, err := os.ReadFile(envFile)
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^NCI_COMMIT_DESCRIPTION<<(ghadelimiter_[0-9a-f]+)\nline 1\nline 2\n(ghadelimiter_[0-9a-f]+)\nNCI_TOKEN<<ghadelimiter_[0-9a-f]+\nsecret\nvalue\nghadelimiter_[0-9a-f]+\n$`), string(content))
+	assert.NotContains(t, string(content), "GITHUB_SHA")
+}
+
+func TestNormalizer_PropagateWithoutEnvFile(t *testing.T) {
+	var normalizer = NewNormalizer()
+	_, err := normalizer.Propagate(map[string]string{}, map[string]string{"NCI": "true"}, api.PropagateOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,22 @@
+package githubactions
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+)
+
+// WriteSummary appends the markdown to the GITHUB_STEP_SUMMARY file, which is shown on the summary page of the workflow run
+func (n Normalizer) WriteSummary(env map[string]string, markdown string) (string, error) {
+	summaryFile := env["GITHUB_STEP_SUMMARY"]
+	if summaryFile == "" {
+		return "", errors.New("GITHUB_STEP_SUMMARY is not set")
+	}
+
+	if !strings.HasSuffix(markdown, "\n") {
+		markdown += "\n"
+	}
+
+	return "", nciutil.AppendToFile(summaryFile, markdown)
+}
@@ -0,0 +1,29 @@
+package githubactions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizer_WriteSummary(t *testing.T) {
+	summaryFile := filepath.Join(t.TempDir(), "step_summary")
+	assert.NoError(t, os.WriteFile(summaryFile, []byte("existing\n"), 0644))
+
+	var normalizer = NewNormalizer()
+	output, err := normalizer.WriteSummary(map[string]string{"GITHUB_STEP_SUMMARY": summaryFile}, "### Build context")
+	assert.NoError(t, err)
+	assert.Empty(t, output)
+
+	content, err := os.ReadFile(summaryFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "existing\n### Build context\n", string(content))
+}
+
+func TestNormalizer_WriteSummaryWithoutFile(t *testing.T) {
+	var normalizer = NewNormalizer()
+	_, err := normalizer.WriteSummary(map[string]string{}, "### Build context")
+	assert.Error(t, err)
+}
@@ -17,6 +17,26 @@ func (n Normalizer) GetSlug() string {
 	return n.slug
 }
 
+// GetVersion returns the version of the normalizer
+func (n Normalizer) GetVersion() string {
+	return n.version
+}
+
+// GetCheckVariables returns the names of the environment variables evaluated by Check
+func (n Normalizer) GetCheckVariables() []string {
+	return []string{"GITLAB_CI"}
+}
+
+// NormalizeTrigger returns the pipeline trigger for a native event name
+func (n Normalizer) NormalizeTrigger(event string) string {
+	return gitlabTriggerNormalize(event)
+}
+
+// SupportsDenormalize returns true if the normalizer can generate the native environment from the common format
+func (n Normalizer) SupportsDenormalize() bool {
+	return true
+}
+
 // Check if this package can handle the current environment
 func (n Normalizer) Check(env map[string]string) bool {
 	return env["GITLAB_CI"] == "true"
@@ -1,13 +1,126 @@
 package gitlabci
 
 import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
 	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
 )
 
+// Denormalize generates the GitLab CI predefined variables from the common format
 func (n Normalizer) Denormalize(spec v1.Spec) (map[string]string, error) {
 	data := make(map[string]string)
 
+	// common
+	data["CI"] = "true"
 	data["GITLAB_CI"] = "true"
+	data["CI_SERVER"] = "yes"
+
+	// server
+	serverURL := gitlabServerURL(spec)
+	data["CI_SERVER_URL"] = serverURL
+	data["CI_SERVER_HOST"] = strings.TrimPrefix(strings.TrimPrefix(serverURL, "https://"), "http://")
+	data["CI_SERVER_PROTOCOL"] = strings.SplitN(serverURL, "://", 2)[0]
+	data["CI_API_V4_URL"] = serverURL + "/api/v4"
+
+	// worker
+	data["CI_RUNNER_ID"] = spec.Worker.Id
+	data["CI_RUNNER_DESCRIPTION"] = spec.Worker.Name
+	data["CI_RUNNER_VERSION"] = spec.Worker.Version
+	data["CI_RUNNER_EXECUTABLE_ARCH"] = spec.Worker.Arch
+
+	// pipeline
+	data["CI_PIPELINE_ID"] = spec.Pipeline.Id
+	data["CI_PIPELINE_SOURCE"] = gitlabTriggerDenormalize(spec.Pipeline.Trigger)
+	data["CI_PIPELINE_URL"] = fmt.Sprintf("%s/-/pipelines/%s", spec.Project.Url, spec.Pipeline.Id)
+	data["CI_JOB_STAGE"] = spec.Pipeline.StageName
+	data["CI_JOB_ID"] = spec.Pipeline.JobId
+	data["CI_JOB_NAME"] = spec.Pipeline.JobName
+	data["CI_JOB_NAME_SLUG"] = spec.Pipeline.JobSlug
+	data["CI_JOB_STARTED_AT"] = spec.Pipeline.JobStartedAt
+	data["CI_JOB_URL"] = spec.Pipeline.Url
+	data["CI_CONFIG_PATH"] = spec.Pipeline.ConfigFile
+
+	// project
+	data["CI_PROJECT_ID"] = spec.Project.Id
+	data["CI_PROJECT_TITLE"] = spec.Project.Name
+	data["CI_PROJECT_NAME"] = path.Base(spec.Project.Path)
+	data["CI_PROJECT_PATH"] = spec.Project.Path
+	data["CI_PROJECT_PATH_SLUG"] = spec.Project.Slug
+	data["CI_PROJECT_NAMESPACE"] = path.Dir(spec.Project.Path)
+	data["CI_PROJECT_ROOT_NAMESPACE"] = strings.SplitN(spec.Project.Path, "/", 2)[0]
+	data["CI_PROJECT_DESCRIPTION"] = spec.Project.Description
+	data["CI_PROJECT_URL"] = spec.Project.Url
+	data["CI_PROJECT_DIR"] = spec.Project.Dir
+	data["CI_DEFAULT_BRANCH"] = spec.Project.DefaultBranch
+	data["CI_REPOSITORY_URL"] = spec.Repository.Remote
+
+	// commit
+	data["CI_COMMIT_SHA"] = spec.Commit.Hash
+	data["CI_COMMIT_SHORT_SHA"] = spec.Commit.HashShort
+	data["CI_COMMIT_REF_NAME"] = spec.Commit.RefName
+	data["CI_COMMIT_REF_SLUG"] = spec.Commit.RefSlug
+	if spec.Commit.RefType == "tag" {
+		data["CI_COMMIT_TAG"] = spec.Commit.RefName
+	} else if spec.MergeRequest.Id == "" {
+		data["CI_COMMIT_BRANCH"] = spec.Commit.RefName
+	}
+	data["CI_COMMIT_TITLE"] = spec.Commit.Title
+	data["CI_COMMIT_DESCRIPTION"] = spec.Commit.Description
+	data["CI_COMMIT_MESSAGE"] = spec.Commit.Title
+	if spec.Commit.Description != "" {
+		data["CI_COMMIT_MESSAGE"] = spec.Commit.Title + "\n\n" + spec.Commit.Description
+	}
+	if spec.Commit.AuthorName != "" {
+		data["CI_COMMIT_AUTHOR"] = fmt.Sprintf("%s <%s>", spec.Commit.AuthorName, spec.Commit.AuthorEmail)
+	}
+
+	// merge request
+	if spec.MergeRequest.Id != "" {
+		data["CI_MERGE_REQUEST_IID"] = spec.MergeRequest.Id
+		data["CI_MERGE_REQUEST_TITLE"] = spec.MergeRequest.Title
+		data["CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"] = spec.MergeRequest.SourceBranchName
+		data["CI_MERGE_REQUEST_SOURCE_BRANCH_SHA"] = spec.MergeRequest.SourceHash
+		data["CI_MERGE_REQUEST_TARGET_BRANCH_NAME"] = spec.MergeRequest.TargetBranchName
+		data["CI_MERGE_REQUEST_TARGET_BRANCH_SHA"] = spec.MergeRequest.TargetHash
+		data["CI_MERGE_REQUEST_PROJECT_ID"] = spec.Project.Id
+		data["CI_MERGE_REQUEST_PROJECT_PATH"] = spec.Project.Path
+		data["CI_MERGE_REQUEST_PROJECT_URL"] = spec.Project.Url
+	}
+
+	// flags, GitLab only sets the variable during an active deploy freeze
+	if spec.Flags.DeployFreeze == "true" {
+		data["CI_DEPLOY_FREEZE"] = "true"
+	}
 
 	return data, nil
 }
+
+func gitlabTriggerDenormalize(trigger string) string {
+	switch trigger {
+	case common.PipelineTriggerMergeRequest:
+		return "merge_request_event"
+	case common.PipelineTriggerManual:
+		return "web"
+	case common.PipelineTriggerBuild:
+		return "pipeline"
+	case common.PipelineTriggerCLI, common.PipelineTriggerUnknown, "":
+		return "push"
+	default:
+		return trigger
+	}
+}
+
+// gitlabServerURL returns the server url, preferring the project url over the repository host
+func gitlabServerURL(spec v1.Spec) string {
+	if spec.Project.Url != "" && spec.Project.Path != "" && strings.HasSuffix(spec.Project.Url, "/"+spec.Project.Path) {
+		return strings.TrimSuffix(spec.Project.Url, "/"+spec.Project.Path)
+	}
+	if spec.Repository.HostServer != "" {
+		return "https://" + spec.Repository.HostServer
+	}
+
+	return "https://gitlab.com"
+}
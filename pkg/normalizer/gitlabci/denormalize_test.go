@@ -0,0 +1,206 @@
+package gitlabci
+
+import (
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSpec = v1.Spec{
+	Worker: v1.Worker{
+		Id:      "12270837",
+		Name:    "4-blue.shared.runners-manager.gitlab.com/default",
+		Version: "17.0.0",
+		Arch:    "linux/amd64",
+	},
+	Pipeline: v1.Pipeline{
+		Id:           "535898514",
+		Trigger:      common.PipelineTriggerPush,
+		StageName:    "build",
+		StageSlug:    "build",
+		JobId:        "2438765887",
+		JobName:      "build binary",
+		JobSlug:      "build-binary",
+		JobStartedAt: "2022-05-10T20:20:01Z",
+		Attempt:      "1",
+		ConfigFile:   ".gitlab-ci.yml",
+		Url:          "https://gitlab.com/cidverse/cienvsamples/-/jobs/2438765887",
+	},
+	Repository: v1.Repository{
+		Remote:     "https://gitlab.com/cidverse/cienvsamples.git",
+		HostServer: "gitlab.com",
+	},
+	Project: v1.Project{
+		Id:            "35974876",
+		Name:          "CI Env Samples",
+		Path:          "cidverse/cienvsamples",
+		Slug:          "cidverse-cienvsamples",
+		Description:   "environment samples",
+		Dir:           "/builds/cidverse/cienvsamples",
+		Url:           "https://gitlab.com/cidverse/cienvsamples",
+		DefaultBranch: "main",
+	},
+	Commit: v1.Commit{
+		RefType:     "branch",
+		RefName:     "feat/denormalize",
+		RefPath:     "branch/feat/denormalize",
+		RefSlug:     "feat-denormalize",
+		RefVCS:      "refs/heads/feat/denormalize",
+		RefRelease:  "feat-denormalize",
+		HashShort:   "790efd9b",
+		Hash:        "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+		AuthorName:  "Jane Doe",
+		AuthorEmail: "jane@example.com",
+		Title:       "feat: add denormalize",
+		Description: "first line\nsecond line",
+	},
+	Flags: v1.Flags{
+		DeployFreeze: "false",
+	},
+}
+
+func TestNormalizer_Denormalize(t *testing.T) {
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(testSpec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "true", denormalized["GITLAB_CI"])
+	assert.Equal(t, "https://gitlab.com", denormalized["CI_SERVER_URL"])
+	assert.Equal(t, "gitlab.com", denormalized["CI_SERVER_HOST"])
+	assert.Equal(t, "https://gitlab.com/api/v4", denormalized["CI_API_V4_URL"])
+	assert.Equal(t, "790efd9b96e59d9b3c3f1899284c85fa91efbcbc", denormalized["CI_COMMIT_SHA"])
+	assert.Equal(t, "790efd9b", denormalized["CI_COMMIT_SHORT_SHA"])
+	assert.Equal(t, "feat/denormalize", denormalized["CI_COMMIT_BRANCH"])
+	assert.Equal(t, "feat-denormalize", denormalized["CI_COMMIT_REF_SLUG"])
+	assert.Equal(t, "feat: add denormalize\n\nfirst line\nsecond line", denormalized["CI_COMMIT_MESSAGE"])
+	assert.Equal(t, "Jane Doe <jane@example.com>", denormalized["CI_COMMIT_AUTHOR"])
+	assert.Equal(t, "cienvsamples", denormalized["CI_PROJECT_NAME"])
+	assert.Equal(t, "cidverse", denormalized["CI_PROJECT_NAMESPACE"])
+	assert.Equal(t, "https://gitlab.com/cidverse/cienvsamples/-/pipelines/535898514", denormalized["CI_PIPELINE_URL"])
+	assert.Equal(t, "push", denormalized["CI_PIPELINE_SOURCE"])
+	assert.NotContains(t, denormalized, "CI_COMMIT_TAG")
+	assert.NotContains(t, denormalized, "CI_MERGE_REQUEST_IID")
+	assert.NotContains(t, denormalized, "CI_DEPLOY_FREEZE")
+}
+
+func TestNormalizer_Denormalize_Tag(t *testing.T) {
+	spec := testSpec
+	spec.Commit.RefType = "tag"
+	spec.Commit.RefName = "v1.2.3"
+
+	var normalizer = NewNormalizer()
+	var denormalized, err = normalizer.Denormalize(spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.3", denormalized["CI_COMMIT_TAG"])
+	assert.NotContains(t, denormalized, "CI_COMMIT_BRANCH")
+}
+
+func TestNormalizer_Denormalize_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(spec *v1.Spec)
+	}{
+		{
+			name:   "push",
+			modify: func(spec *v1.Spec) {},
+		},
+		{
+			name: "tag",
+			modify: func(spec *v1.Spec) {
+				spec.Commit.RefType = "tag"
+				spec.Commit.RefName = "v1.2.3"
+				spec.Commit.RefPath = "tag/v1.2.3"
+				spec.Commit.RefSlug = "v1-2-3"
+				spec.Commit.RefVCS = "refs/tags/v1.2.3"
+				spec.Commit.RefRelease = "1.2.3"
+			},
+		},
+		{
+			name: "merge request",
+			modify: func(spec *v1.Spec) {
+				spec.Pipeline.Trigger = common.PipelineTriggerMergeRequest
+				spec.MergeRequest = v1.MergeRequest{
+					Id:               "153",
+					Title:            "Draft: add denormalize",
+					SourceBranchName: "feat/denormalize",
+					SourceHash:       "790efd9b96e59d9b3c3f1899284c85fa91efbcbc",
+					TargetBranchName: "main",
+					TargetHash:       "4a9b2f1c8e0d7a6b5c4d3e2f1a0b9c8d7e6f5a4b",
+				}
+			},
+		},
+		{
+			name: "manual with deploy freeze",
+			modify: func(spec *v1.Spec) {
+				spec.Pipeline.Trigger = common.PipelineTriggerManual
+				spec.Flags.DeployFreeze = "true"
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nciutil.MockVCSClient(t)
+
+			spec := testSpec
+			test.modify(&spec)
+
+			var normalizer = NewNormalizer()
+			var denormalized, err = normalizer.Denormalize(spec)
+			assert.NoError(t, err)
+			assert.True(t, normalizer.Check(denormalized))
+
+			var normalized, normalizeErr = normalizer.Normalize(denormalized)
+			assert.NoError(t, normalizeErr)
+
+			assert.Equal(t, spec.Worker.Id, normalized.Worker.Id)
+			assert.Equal(t, spec.Worker.Name, normalized.Worker.Name)
+			assert.Equal(t, spec.Worker.Version, normalized.Worker.Version)
+			assert.Equal(t, spec.Pipeline, normalized.Pipeline)
+			assert.Equal(t, spec.MergeRequest, normalized.MergeRequest)
+			assert.Equal(t, spec.Flags, normalized.Flags)
+			assert.Equal(t, spec.Commit.RefType, normalized.Commit.RefType)
+			assert.Equal(t, spec.Commit.RefName, normalized.Commit.RefName)
+			assert.Equal(t, spec.Commit.RefPath, normalized.Commit.RefPath)
+			assert.Equal(t, spec.Commit.RefSlug, normalized.Commit.RefSlug)
+			assert.Equal(t, spec.Commit.RefVCS, normalized.Commit.RefVCS)
+			assert.Equal(t, spec.Commit.RefRelease, normalized.Commit.RefRelease)
+			assert.Equal(t, spec.Commit.Hash, normalized.Commit.Hash)
+			assert.Equal(t, spec.Commit.HashShort, normalized.Commit.HashShort)
+			assert.Equal(t, spec.Commit.Title, normalized.Commit.Title)
+			assert.Equal(t, spec.Commit.Description, normalized.Commit.Description)
+			assert.Equal(t, spec.Commit.AuthorName, normalized.Commit.AuthorName)
+			assert.Equal(t, spec.Commit.AuthorEmail, normalized.Commit.AuthorEmail)
+			assert.Equal(t, spec.Project.Id, normalized.Project.Id)
+			assert.Equal(t, spec.Project.Name, normalized.Project.Name)
+			assert.Equal(t, spec.Project.Path, normalized.Project.Path)
+			assert.Equal(t, spec.Project.Slug, normalized.Project.Slug)
+			assert.Equal(t, spec.Project.Description, normalized.Project.Description)
+			assert.Equal(t, spec.Project.Url, normalized.Project.Url)
+			assert.Equal(t, spec.Project.DefaultBranch, normalized.Project.DefaultBranch)
+		})
+	}
+}
+
+func TestParseGitlabAuthor(t *testing.T) {
+	tests := []struct {
+		input string
+		name  string
+		email string
+	}{
+		{"Jane Doe <jane@example.com>", "Jane Doe", "jane@example.com"},
+		{"Jane <Doe> <jane@example.com>", "Jane <Doe>", "jane@example.com"},
+		{"Jane Doe", "Jane Doe", ""},
+		{"", "", ""},
+	}
+
+	for _, test := range tests {
+		name, email := parseGitlabAuthor(test.input)
+		assert.Equal(t, test.name, name, test.input)
+		assert.Equal(t, test.email, email, test.input)
+	}
+}
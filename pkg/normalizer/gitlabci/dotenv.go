@@ -0,0 +1,52 @@
+package gitlabci
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+)
+
+// limits of the dotenv report on GitLab.com, see https://docs.gitlab.com/ee/ci/yaml/artifacts_reports.html#artifactsreportsdotenv
+// self-managed instances default to 20 variables, the limits are configured per instance and can be passed with DotenvLimits
+const (
+	DotenvMaxSize      = 5 * 1024 // DotenvMaxSize is the maximum size of a dotenv report in bytes
+	DotenvMaxVariables = 150      // DotenvMaxVariables is the maximum number of variables in a dotenv report
+)
+
+// DotenvLimits holds the limits of the GitLab instance for dotenv reports, zero values use the GitLab.com limits
+type DotenvLimits struct {
+	MaxVariables int // MaxVariables is the maximum number of variables in a dotenv report
+	MaxSize      int // MaxSize is the maximum size of a dotenv report in bytes
+}
+
+// FormatDotenv renders a GitLab dotenv report, which doesn't support quoting, multiline values, empty lines or comments.
+// Line breaks are encoded as \n, a report that exceeds the size or variable limits is rejected.
+func FormatDotenv(vars map[string]string, limits DotenvLimits) (string, error) {
+	maxVariables := limits.MaxVariables
+	if maxVariables == 0 {
+		maxVariables = DotenvMaxVariables
+	}
+	maxSize := limits.MaxSize
+	if maxSize == 0 {
+		maxSize = DotenvMaxSize
+	}
+
+	keys, err := nciutil.SortedEnvKeys(vars)
+	if err != nil {
+		return "", err
+	}
+	if len(keys) > maxVariables {
+		return "", fmt.Errorf("gitlab dotenv reports are limited to %d variables, got %d", maxVariables, len(keys))
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("%s=%s\n", key, nciutil.EncodeLineBreaks(vars[key])))
+	}
+	if sb.Len() > maxSize {
+		return "", fmt.Errorf("gitlab dotenv reports are limited to %d bytes, got %d", maxSize, sb.Len())
+	}
+
+	return sb.String(), nil
+}
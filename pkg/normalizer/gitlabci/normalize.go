@@ -3,11 +3,11 @@ package gitlabci
 import (
 	"fmt"
 	"runtime"
+	"strings"
 
 	"github.com/cidverse/go-vcs/vcsutil"
 	"github.com/cidverse/normalizeci/pkg/ncispec/common"
 	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
-	"github.com/cidverse/normalizeci/pkg/nciutil"
 	"github.com/cidverse/normalizeci/pkg/projectdetails"
 	"github.com/cidverse/normalizeci/pkg/vcsrepository"
 	"github.com/gosimple/slug"
@@ -16,52 +16,64 @@ import (
 
 // Normalize normalizes the environment variables into the common format
 func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
+	nci, _, err := n.NormalizeWithProvenance(env)
+	return nci, err
+}
+
+// NormalizeWithProvenance normalizes the environment variables into the common format and records the source of each field
+func (n Normalizer) NormalizeWithProvenance(env map[string]string) (v1.Spec, v1.Provenance, error) {
 	nci := v1.Create(n.name, n.slug)
+	prov := v1.Provenance{}
 
 	// worker
 	nci.Worker = v1.Worker{
-		Id:      env["CI_RUNNER_ID"],
-		Name:    env["CI_RUNNER_DESCRIPTION"],
+		Id:      prov.Env("worker.id", env, "CI_RUNNER_ID"),
+		Name:    prov.Env("worker.name", env, "CI_RUNNER_DESCRIPTION"),
 		Type:    "gitlab_hosted_vm",
 		OS:      "",
-		Version: env["CI_RUNNER_VERSION"],
+		Version: prov.Env("worker.version", env, "CI_RUNNER_VERSION"),
 		Arch:    runtime.GOOS + "/" + runtime.GOARCH,
 	}
 
 	// pipeline
-	nci.Pipeline.Id = env["CI_PIPELINE_ID"]
-	nci.Pipeline.Trigger = gitlabTriggerNormalize(env["CI_PIPELINE_SOURCE"])
-	nci.Pipeline.StageName = env["CI_JOB_STAGE"]
-	nci.Pipeline.StageSlug = slug.Make(env["CI_JOB_STAGE"])
-	nci.Pipeline.JobId = env["CI_JOB_ID"]
-	nci.Pipeline.JobName = env["CI_JOB_NAME"]
-	nci.Pipeline.JobSlug = slug.Make(env["CI_JOB_NAME"])
-	nci.Pipeline.JobStartedAt = env["CI_JOB_STARTED_AT"]
+	nci.Pipeline.Id = prov.Env("pipeline.id", env, "CI_PIPELINE_ID")
+	nci.Pipeline.Trigger = gitlabTriggerNormalize(prov.Env("pipeline.trigger", env, "CI_PIPELINE_SOURCE"))
+	nci.Pipeline.StageName = prov.Env("pipeline.stageName", env, "CI_JOB_STAGE")
+	nci.Pipeline.StageSlug = slug.Make(prov.Env("pipeline.stageSlug", env, "CI_JOB_STAGE"))
+	nci.Pipeline.JobId = prov.Env("pipeline.jobId", env, "CI_JOB_ID")
+	nci.Pipeline.JobName = prov.Env("pipeline.jobName", env, "CI_JOB_NAME")
+	nci.Pipeline.JobSlug = slug.Make(prov.Env("pipeline.jobSlug", env, "CI_JOB_NAME"))
+	nci.Pipeline.JobStartedAt = prov.Env("pipeline.jobStartedAt", env, "CI_JOB_STARTED_AT")
 	nci.Pipeline.Attempt = "1"
-	nci.Pipeline.ConfigFile = "gitlab-ci.yml"
-	nci.Pipeline.Url = env["CI_JOB_URL"]
+	nci.Pipeline.ConfigFile = prov.First("pipeline.configFile", v1.EnvCandidate(env, "CI_CONFIG_PATH"), v1.SourceCandidate("gitlab-ci.yml", v1.SourceDefault, ""))
+	nci.Pipeline.Url = prov.Env("pipeline.url", env, "CI_JOB_URL")
 
 	// merge request
-	if mergeRequestId, isMergeRequest := env["CI_MERGE_REQUEST_IID"]; isMergeRequest {
-		nci.MergeRequest.Id = mergeRequestId
-		nci.MergeRequest.SourceBranchName = env["CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"]
-		nci.MergeRequest.SourceHash = env["CI_MERGE_REQUEST_SOURCE_BRANCH_SHA"]
-		nci.MergeRequest.TargetBranchName = env["CI_MERGE_REQUEST_TARGET_BRANCH_NAME"]
-		nci.MergeRequest.TargetHash = env["CI_MERGE_REQUEST_SOURCE_BRANCH_SHA"]
+	if _, isMergeRequest := env["CI_MERGE_REQUEST_IID"]; isMergeRequest {
+		nci.MergeRequest.Id = prov.Env("mergeRequest.id", env, "CI_MERGE_REQUEST_IID")
+		nci.MergeRequest.Title = prov.Env("mergeRequest.title", env, "CI_MERGE_REQUEST_TITLE")
+		nci.MergeRequest.SourceBranchName = prov.Env("mergeRequest.sourceBranchName", env, "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME")
+		nci.MergeRequest.SourceHash = prov.Env("mergeRequest.sourceHash", env, "CI_MERGE_REQUEST_SOURCE_BRANCH_SHA")
+		nci.MergeRequest.TargetBranchName = prov.Env("mergeRequest.targetBranchName", env, "CI_MERGE_REQUEST_TARGET_BRANCH_NAME")
+		nci.MergeRequest.TargetHash = prov.Env("mergeRequest.targetHash", env, "CI_MERGE_REQUEST_TARGET_BRANCH_SHA")
 	}
 
 	// repository
 	projectDir, err := vcsutil.FindProjectDirectoryFromWorkDir()
 	if err != nil {
-		return nci, fmt.Errorf("failed to find project directory: %v", err)
+		return nci, prov, fmt.Errorf("failed to find project directory: %v", err)
 	}
 	vcsData, err := vcsrepository.GetVCSRepositoryInformation(projectDir)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get repository details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get repository details: %v", err)
 	}
 	nci.Repository = vcsData.Repository
 	nci.Commit = vcsData.Commit
+	prov.SetSection(nci, "repository", v1.SourceGit, "")
+	prov.SetSection(nci, "commit", v1.SourceGit, "HEAD")
+	refKey := "CI_COMMIT_REF_NAME"
 	if len(env["CI_COMMIT_TAG"]) > 0 {
+		refKey = "CI_COMMIT_TAG"
 		nci.Commit.RefType = "tag"
 		nci.Commit.RefName = env["CI_COMMIT_TAG"]
 		nci.Commit.RefPath = nci.Commit.RefType + "/" + env["CI_COMMIT_TAG"]
@@ -75,6 +87,19 @@ func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
 		nci.Commit.RefVCS = "refs/heads/" + env["CI_COMMIT_REF_NAME"]
 	}
 	nci.Commit.RefRelease = vcsrepository.ToReleaseName(nci.Commit.RefName)
+	for _, field := range []string{"commit.refType", "commit.refName", "commit.refPath", "commit.refSlug", "commit.refVcs", "commit.refRelease"} {
+		prov.Set(field, v1.SourceEnv, refKey)
+	}
+	nci.Commit.Hash = prov.First("commit.hash", v1.EnvCandidate(env, "CI_COMMIT_SHA"), v1.SourceCandidate(nci.Commit.Hash, v1.SourceGit, "HEAD"))
+	nci.Commit.HashShort = prov.First("commit.hashShort", v1.EnvCandidate(env, "CI_COMMIT_SHORT_SHA"), v1.SourceCandidate(nci.Commit.HashShort, v1.SourceGit, "HEAD"))
+	nci.Commit.Title = prov.First("commit.title", v1.EnvCandidate(env, "CI_COMMIT_TITLE"), v1.SourceCandidate(nci.Commit.Title, v1.SourceGit, "HEAD"))
+	nci.Commit.Description = prov.First("commit.description", v1.EnvCandidate(env, "CI_COMMIT_DESCRIPTION"), v1.SourceCandidate(nci.Commit.Description, v1.SourceGit, "HEAD"))
+	if authorName, authorEmail := parseGitlabAuthor(env["CI_COMMIT_AUTHOR"]); authorName != "" {
+		nci.Commit.AuthorName = authorName
+		nci.Commit.AuthorEmail = authorEmail
+		prov.Set("commit.authorName", v1.SourceEnv, "CI_COMMIT_AUTHOR")
+		prov.Set("commit.authorEmail", v1.SourceEnv, "CI_COMMIT_AUTHOR")
+	}
 
 	// project details
 	projectData, err := projectdetails.GetProjectDetails(nci.Repository.Kind, nci.Repository.Remote, nci.Repository.HostType, nci.Repository.HostServer)
@@ -82,39 +107,45 @@ func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
 		// CI_JOB_TOKEN read_project access is pending for 6 years (https://gitlab.com/gitlab-org/gitlab/-/issues/17511)
 		log.Debug().Err(err).Msg("failed to get project details")
 	}
-	nci.Project.Id = nciutil.FirstNonEmpty([]string{nciutil.GetValueFromMap(env, "CI_PROJECT_ID"), projectData.Id})
-	nci.Project.Name = nciutil.FirstNonEmpty([]string{nciutil.GetValueFromMap(env, "CI_PROJECT_TITLE"), projectData.Name})
-	nci.Project.Path = nciutil.FirstNonEmpty([]string{nciutil.GetValueFromMap(env, "CI_PROJECT_PATH"), projectData.Path})
-	nci.Project.Slug = nciutil.FirstNonEmpty([]string{nciutil.GetValueFromMap(env, "CI_PROJECT_PATH_SLUG"), projectData.Slug})
-	nci.Project.Description = nciutil.FirstNonEmpty([]string{nciutil.GetValueFromMap(env, "CI_PROJECT_DESCRIPTION"), projectData.Description})
-	nci.Project.Topics = nciutil.FirstNonEmpty([]string{projectData.Topics})
-	nci.Project.IssueUrl = nciutil.FirstNonEmpty([]string{projectData.IssueUrl})
-	nci.Project.Stargazers = nciutil.FirstNonEmpty([]string{projectData.Stargazers})
-	nci.Project.Forks = nciutil.FirstNonEmpty([]string{projectData.Forks})
-	nci.Project.DefaultBranch = nciutil.FirstNonEmpty([]string{nciutil.GetValueFromMap(env, "CI_DEFAULT_BRANCH"), projectData.DefaultBranch})
-	nci.Project.Url = nciutil.FirstNonEmpty([]string{nciutil.GetValueFromMap(env, "CI_PROJECT_URL"), projectData.Url})
+	apiKey := nci.Repository.HostType
+	nci.Project.Id = prov.First("project.id", v1.EnvCandidate(env, "CI_PROJECT_ID"), v1.SourceCandidate(projectData.Id, v1.SourceAPI, apiKey))
+	nci.Project.Name = prov.First("project.name", v1.EnvCandidate(env, "CI_PROJECT_TITLE"), v1.SourceCandidate(projectData.Name, v1.SourceAPI, apiKey))
+	nci.Project.Path = prov.First("project.path", v1.EnvCandidate(env, "CI_PROJECT_PATH"), v1.SourceCandidate(projectData.Path, v1.SourceAPI, apiKey))
+	nci.Project.Slug = prov.First("project.slug", v1.EnvCandidate(env, "CI_PROJECT_PATH_SLUG"), v1.SourceCandidate(projectData.Slug, v1.SourceAPI, apiKey))
+	nci.Project.Description = prov.First("project.description", v1.EnvCandidate(env, "CI_PROJECT_DESCRIPTION"), v1.SourceCandidate(projectData.Description, v1.SourceAPI, apiKey))
+	nci.Project.Topics = prov.First("project.topics", v1.SourceCandidate(projectData.Topics, v1.SourceAPI, apiKey))
+	nci.Project.IssueUrl = prov.First("project.issueUrl", v1.SourceCandidate(projectData.IssueUrl, v1.SourceAPI, apiKey))
+	nci.Project.Stargazers = prov.First("project.stargazers", v1.SourceCandidate(projectData.Stargazers, v1.SourceAPI, apiKey))
+	nci.Project.Forks = prov.First("project.forks", v1.SourceCandidate(projectData.Forks, v1.SourceAPI, apiKey))
+	nci.Project.DefaultBranch = prov.First("project.defaultBranch", v1.EnvCandidate(env, "CI_DEFAULT_BRANCH"), v1.SourceCandidate(projectData.DefaultBranch, v1.SourceAPI, apiKey))
+	nci.Project.Url = prov.First("project.url", v1.EnvCandidate(env, "CI_PROJECT_URL"), v1.SourceCandidate(projectData.Url, v1.SourceAPI, apiKey))
 	nci.Project.Dir = projectDir
+	prov.Set("project.dir", v1.SourceGit, "")
 
 	// flags
 	if _, ok := env["CI_DEPLOY_FREEZE"]; ok {
-		nci.Flags.DeployFreeze = env["CI_DEPLOY_FREEZE"]
+		nci.Flags.DeployFreeze = prov.Env("flags.deployFreeze", env, "CI_DEPLOY_FREEZE")
 	} else {
-		nci.Flags.DeployFreeze = "false"
+		nci.Flags.DeployFreeze = prov.Default("flags.deployFreeze", "false")
 	}
 
-	// custom input parameters
-	variables, err := GetGitlabPipelineRun(env["CI_SERVER_URL"], env["CI_PROJECT_ID"], env["CI_PIPELINE_ID"], env["CI_JOB_TOKEN"])
-	if err == nil {
-		v := make(map[string]string)
+	// custom input parameters, the pipeline variables can't be queried without a job token
+	if env["CI_JOB_TOKEN"] != "" {
+		variables, err := GetGitlabPipelineRun(env["CI_SERVER_URL"], env["CI_PROJECT_ID"], env["CI_PIPELINE_ID"], env["CI_JOB_TOKEN"])
+		if err == nil {
+			v := make(map[string]string)
 
-		for _, variable := range variables {
-			v[variable.Key] = variable.Value
-		}
+			for _, variable := range variables {
+				v[variable.Key] = variable.Value
+				prov.Set("pipeline.inputs."+variable.Key, v1.SourceAPI, "pipelines/variables")
+			}
 
-		nci.Pipeline.Input = v
+			nci.Pipeline.Input = v
+		}
 	}
 
-	return nci, nil
+	prov.Complete(nci)
+	return nci, prov, nil
 }
 
 func gitlabTriggerNormalize(input string) string {
@@ -124,6 +155,25 @@ func gitlabTriggerNormalize(input string) string {
 	if input == "schedule" {
 		return common.PipelineTriggerSchedule
 	}
+	if input == "web" || input == "api" || input == "chat" || input == "webide" {
+		return common.PipelineTriggerManual
+	}
+	if input == "pipeline" || input == "parent_pipeline" || input == "trigger" {
+		return common.PipelineTriggerBuild
+	}
+	if input == "push" {
+		return common.PipelineTriggerPush
+	}
+
+	return common.PipelineTriggerUnknown
+}
+
+// parseGitlabAuthor splits CI_COMMIT_AUTHOR (ie. `Name <email>`) into name and email
+func parseGitlabAuthor(input string) (string, string) {
+	start := strings.LastIndex(input, " <")
+	if start < 0 || !strings.HasSuffix(input, ">") {
+		return strings.TrimSpace(input), ""
+	}
 
-	return input
+	return input[:start], input[start+2 : len(input)-1]
 }
@@ -2,10 +2,13 @@ package gitlabci
 
 import (
 	_ "embed"
+	"net/http"
 	"runtime"
 	"testing"
 
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
 	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -67,6 +70,32 @@ func TestNormalizer_Normalize_Pipeline(t *testing.T) {
 	assert.Equal(t, "https://gitlab.com/cidverse/cienvsamples/-/jobs/2438765887", normalized.Pipeline.Url)
 }
 
+func TestNormalizer_Normalize_Trigger(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	tests := map[string]string{
+		"push":                          "push",
+		"web":                           "manual",
+		"api":                           "manual",
+		"chat":                          "manual",
+		"webide":                        "manual",
+		"trigger":                       "build",
+		"pipeline":                      "build",
+		"parent_pipeline":               "build",
+		"schedule":                      "schedule",
+		"merge_request_event":           "merge_request",
+		"external_pull_request_event":   "merge_request",
+		"security_orchestration_policy": "unknown",
+	}
+	for source, trigger := range tests {
+		var normalizer = NewNormalizer()
+		var normalized, err = normalizer.Normalize(map[string]string{"CI_PIPELINE_SOURCE": source})
+
+		assert.NoError(t, err, source)
+		assert.Equal(t, trigger, normalized.Pipeline.Trigger, source)
+	}
+}
+
 func TestNormalizer_Normalize_MergeRequest(t *testing.T) {
 	nciutil.MockVCSClient(t)
 
@@ -111,3 +140,45 @@ func TestNormalizer_Normalize_Project(t *testing.T) {
 func TestNormalizer_Normalize_WorkflowAPI(t *testing.T) {
 
 }
+
+func TestNormalizer_Normalize_WithoutJobToken(t *testing.T) {
+	nciutil.MockVCSClient(t)
+	gitlabMockClient = &http.Client{}
+	httpmock.ActivateNonDefault(gitlabMockClient)
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "https://gitlab.com/api/v4/projects/43228743/pipelines/801916361/variables", httpmock.NewStringResponder(200, `[{"variable_type":"env_var","key":"hello","value":"world","raw":false}]`))
+
+	var normalizer = NewNormalizer()
+	var normalized, err = normalizer.Normalize(map[string]string{
+		"CI_SERVER_URL":  "https://gitlab.com",
+		"CI_PROJECT_ID":  "43228743",
+		"CI_PIPELINE_ID": "801916361",
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, normalized.Pipeline.Input)
+	assert.Equal(t, 0, httpmock.GetTotalCallCount())
+}
+
+func TestNormalizer_NormalizeWithProvenance(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	var normalizer = NewNormalizer()
+	var normalized, prov, err = normalizer.NormalizeWithProvenance(map[string]string{
+		"CI_PROJECT_TITLE":   "cienvsamples",
+		"CI_COMMIT_TAG":      "v1.2.0",
+		"CI_COMMIT_SHA":      "5e48b2b4b2ae1c1c8c7b0b9b2f1bc2a5bbdd4f1a",
+		"CI_RUNNER_ID":       "12270837",
+		"CI_PIPELINE_SOURCE": "push",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cienvsamples", normalized.Project.Name)
+	assert.Equal(t, v1.Source{Kind: v1.SourceEnv, Key: "CI_PROJECT_TITLE"}, prov["project.name"])
+	assert.Equal(t, v1.Source{Kind: v1.SourceEnv, Key: "CI_COMMIT_TAG"}, prov["commit.refRelease"])
+	assert.Equal(t, v1.Source{Kind: v1.SourceEnv, Key: "CI_COMMIT_SHA"}, prov["commit.hash"])
+	assert.Equal(t, v1.Source{Kind: v1.SourceAPI, Key: "github"}, prov["project.path"])
+	assert.Equal(t, v1.Source{Kind: v1.SourceDefault, Key: ""}, prov["pipeline.configFile"])
+	assert.Equal(t, v1.Source{Kind: v1.SourceDefault, Key: ""}, prov["flags.deployFreeze"])
+	assert.Equal(t, v1.SourceGit, prov["repository.remote"].Kind)
+}
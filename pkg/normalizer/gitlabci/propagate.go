@@ -0,0 +1,37 @@
+package gitlabci
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/rs/zerolog/log"
+)
+
+// Propagate writes a dotenv report, the file must be declared as `artifacts:reports:dotenv` to pass the variables to later jobs.
+// Artifacts can be downloaded by everyone with access to the pipeline, therefore secrets are not written into the report.
+func (n Normalizer) Propagate(env map[string]string, vars map[string]string, opts api.PropagateOptions) (string, error) {
+	if opts.File == "" {
+		return "", errors.New("no dotenv report file provided")
+	}
+
+	report := make(map[string]string, len(vars))
+	for key, value := range vars {
+		if opts.IsSecret(key) {
+			log.Warn().Str("key", key).Msg("skipping secret, dotenv reports are stored as downloadable artifact")
+			continue
+		}
+		report[key] = value
+	}
+
+	content, err := FormatDotenv(report, DotenvLimits{MaxVariables: opts.DotenvMaxVariables, MaxSize: opts.DotenvMaxSize})
+	if err != nil {
+		return "", err
+	}
+	if err = os.WriteFile(opts.File, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write dotenv report: %w", err)
+	}
+
+	return "", nil
+}
@@ -0,0 +1,41 @@
+package gitlabci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizer_Propagate(t *testing.T) {
+	reportFile := filepath.Join(t.TempDir(), "nci.env")
+
+	var normalizer = NewNormalizer()
+	output, err := normalizer.Propagate(
+		map[string]string{},
+		map[string]string{"NCI_COMMIT_DESCRIPTION": "line 1\nline 2", "NCI_TOKEN": "secret"},
+		api.PropagateOptions{Secrets: []string{"NCI_TOKEN"}, File: reportFile},
+	)
+
+	assert.NoError(t, err)
+	assert.Empty(t, output)
+	content, err := os.ReadFile(reportFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "NCI_COMMIT_DESCRIPTION=line 1\\nline 2\n", string(content))
+}
+
+func TestNormalizer_Propagate_Limits(t *testing.T) {
+	reportFile := filepath.Join(t.TempDir(), "nci.env")
+
+	var normalizer = NewNormalizer()
+	_, err := normalizer.Propagate(
+		map[string]string{},
+		map[string]string{"NCI_A": "a", "NCI_B": "b"},
+		api.PropagateOptions{File: reportFile, DotenvMaxVariables: 1},
+	)
+
+	assert.ErrorContains(t, err, "limited to 1 variables")
+	assert.NoFileExists(t, reportFile)
+}
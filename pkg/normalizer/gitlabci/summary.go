@@ -0,0 +1,21 @@
+package gitlabci
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// summarySection is the name of the collapsible section in the job log
+const summarySection = "nci_summary"
+
+// WriteSummary prints the markdown as collapsed section into the job log, GitLab has no dedicated summary page.
+// See https://docs.gitlab.com/ee/ci/jobs/job_logs.html#custom-collapsible-sections
+func (n Normalizer) WriteSummary(env map[string]string, markdown string) (string, error) {
+	if !strings.HasSuffix(markdown, "\n") {
+		markdown += "\n"
+	}
+
+	now := time.Now().Unix()
+	return fmt.Sprintf("\x1b[0Ksection_start:%d:%s[collapsed=true]\r\x1b[0KBuild context\n%s\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", now, summarySection, markdown, now, summarySection), nil
+}
@@ -0,0 +1,16 @@
+package gitlabci
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizer_WriteSummary(t *testing.T) {
+	var normalizer = NewNormalizer()
+	output, err := normalizer.WriteSummary(map[string]string{}, "### Build context")
+
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile("^\x1b\\[0Ksection_start:[0-9]+:nci_summary\\[collapsed=true\\]\r\x1b\\[0KBuild context\n### Build context\n\x1b\\[0Ksection_end:[0-9]+:nci_summary\r\x1b\\[0K\n$"), output)
+}
@@ -30,14 +30,41 @@ func (n Normalizer) GetSlug() string {
 	return n.slug
 }
 
+// GetVersion returns the version of the normalizer
+func (n Normalizer) GetVersion() string {
+	return n.version
+}
+
+// SupportsDenormalize returns true if the normalizer can generate the native environment from the common format
+func (n Normalizer) SupportsDenormalize() bool {
+	return false
+}
+
 // Check if this package can handle the current environment
 func (n Normalizer) Check(env map[string]string) bool {
 	return true
 }
 
+// DescribeCheck returns the git repository used if no ci service matches the environment
+func (n Normalizer) DescribeCheck(env map[string]string) string {
+	projectDir, err := vcsutil.FindProjectDirectoryFromWorkDir()
+	if err != nil {
+		return "fallback if no ci service matches, no git repository found"
+	}
+
+	return "fallback if no ci service matches, git repository at " + projectDir
+}
+
 // Normalize normalizes the environment variables into the common format
 func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
+	nci, _, err := n.NormalizeWithProvenance(env)
+	return nci, err
+}
+
+// NormalizeWithProvenance normalizes the environment variables into the common format and records the source of each field
+func (n Normalizer) NormalizeWithProvenance(env map[string]string) (v1.Spec, v1.Provenance, error) {
 	nci := v1.Create(n.name, n.slug)
+	prov := v1.Provenance{}
 
 	// worker
 	nci.Worker.Id = "local"
@@ -62,27 +89,32 @@ func (n Normalizer) Normalize(env map[string]string) (v1.Spec, error) {
 	// repository
 	projectDir, err := vcsutil.FindProjectDirectoryFromWorkDir()
 	if err != nil {
-		return nci, fmt.Errorf("failed to find project directory: %v", err)
+		return nci, prov, fmt.Errorf("failed to find project directory: %v", err)
 	}
 	vcsData, err := vcsrepository.GetVCSRepositoryInformation(projectDir)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get repository details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get repository details: %v", err)
 	}
 	nci.Repository = vcsData.Repository
 	nci.Commit = vcsData.Commit
+	prov.SetSection(nci, "repository", v1.SourceGit, "")
+	prov.SetSection(nci, "commit", v1.SourceGit, "HEAD")
 
 	// project details
 	projectData, err := projectdetails.GetProjectDetails(nci.Repository.Kind, nci.Repository.Remote, nci.Repository.HostType, nci.Repository.HostServer)
 	if err != nil {
-		return nci, fmt.Errorf("failed to get project details: %v", err)
+		return nci, prov, fmt.Errorf("failed to get project details: %v", err)
 	}
 	nci.Project = projectData
+	prov.SetSection(nci, "project", v1.SourceAPI, nci.Repository.HostType)
 	nci.Project.Dir = projectDir
+	prov.Set("project.dir", v1.SourceGit, "")
 
 	// flags
 	nci.Flags.DeployFreeze = "false"
 
-	return nci, nil
+	prov.Complete(nci)
+	return nci, prov, nil
 }
 
 func (n Normalizer) Denormalize(spec v1.Spec) (map[string]string, error) {
@@ -4,14 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"runtime"
-	"strings"
 
 	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
 	"github.com/cidverse/normalizeci/pkg/normalizer/api"
 	"github.com/cidverse/normalizeci/pkg/normalizer/appveyor"
 	"github.com/cidverse/normalizeci/pkg/normalizer/azuredevops"
 	"github.com/cidverse/normalizeci/pkg/normalizer/circleci"
+	"github.com/cidverse/normalizeci/pkg/normalizer/generic"
 	"github.com/cidverse/normalizeci/pkg/normalizer/githubactions"
 	"github.com/cidverse/normalizeci/pkg/normalizer/gitlabci"
 	"github.com/cidverse/normalizeci/pkg/normalizer/localgit"
@@ -27,9 +26,15 @@ func init() {
 	normalizers = append(normalizers, circleci.NewNormalizer())
 	normalizers = append(normalizers, githubactions.NewNormalizer())
 	normalizers = append(normalizers, gitlabci.NewNormalizer())
+	normalizers = append(normalizers, generic.NewNormalizer())
 	normalizers = append(normalizers, localgit.NewNormalizer())
 }
 
+// GetNormalizers returns all registered normalizers
+func GetNormalizers() []api.Normalizer {
+	return normalizers
+}
+
 func Normalize() (v1.Spec, error) {
 	env := api.GetMachineEnvironment()
 	return NormalizeEnv(env)
@@ -37,97 +42,139 @@ func Normalize() (v1.Spec, error) {
 
 // NormalizeEnv executes the ci normalization for all supported services
 func NormalizeEnv(env map[string]string) (v1.Spec, error) {
-	// normalize (iterate over all supported systems and normalize variables if possible)
+	normalizer, err := DetectNormalizer(env)
+	if err != nil {
+		return v1.Spec{}, err
+	}
+
+	return normalizer.Normalize(env)
+}
+
+// NormalizeEnvWithProvenance executes the ci normalization and returns the source of each field, fields of normalizers without provenance support are recorded as default
+func NormalizeEnvWithProvenance(env map[string]string) (v1.Spec, v1.Provenance, error) {
+	normalizer, err := DetectNormalizer(env)
+	if err != nil {
+		return v1.Spec{}, nil, err
+	}
+
+	if provenanceNormalizer, ok := normalizer.(api.ProvenanceNormalizer); ok {
+		return provenanceNormalizer.NormalizeWithProvenance(env)
+	}
+
+	spec, err := normalizer.Normalize(env)
+	prov := v1.Provenance{}
+	prov.Complete(spec)
+	return spec, prov, err
+}
+
+// DetectNormalizer returns the first normalizer that can handle the environment
+func DetectNormalizer(env map[string]string) (api.Normalizer, error) {
+	// iterate over all supported systems, the first match wins
 	for _, normalizer := range normalizers {
 		if normalizer.Check(env) {
 			log.Debug().Msg("Matched " + normalizer.GetName() + ", not checking for any other matches.")
-			normalized, err := normalizer.Normalize(env)
-			return normalized, err
+			return normalizer, nil
 		} else {
 			log.Debug().Msg("Didn't match in " + normalizer.GetName())
 		}
 	}
 
-	return v1.Spec{}, errors.New("no matching normalizer found")
+	return nil, errors.New("no matching normalizer found")
 }
 
-// Denormalize will generate ci variables for the target service
-func Denormalize(target string, env v1.Spec) (map[string]string, error) {
-	// denormalize
+// Detection is the result of the Check of a normalizer
+type Detection struct {
+	Name      string            `json:"name"`
+	Slug      string            `json:"slug"`
+	Matched   bool              `json:"matched"`           // Matched is true if the Check of the normalizer matched the environment
+	Selected  bool              `json:"selected"`          // Selected is true for the first match, which is used for normalization
+	Variables map[string]string `json:"variables"`         // Variables holds the values of the environment variables evaluated by the Check
+	Details   string            `json:"details,omitempty"` // Details describes what the Check evaluates, for normalizers that don't detect the ci service by environment variables
+}
+
+// DetectAll evaluates the Check of all normalizers, unlike DetectNormalizer it doesn't stop at the first match
+func DetectAll(env map[string]string) []Detection {
+	var detections []Detection
+	selected := false
 	for _, normalizer := range normalizers {
-		if target == normalizer.GetSlug() {
-			log.Debug().Msg("Matched " + normalizer.GetName() + ", not checking for any other matches.")
-			normalized, err := normalizer.Denormalize(env)
-			return normalized, err
-		} else {
-			log.Debug().Msg("Didn't match in " + normalizer.GetName())
+		detection := Detection{
+			Name:      normalizer.GetName(),
+			Slug:      normalizer.GetSlug(),
+			Matched:   normalizer.Check(env),
+			Variables: make(map[string]string),
 		}
+		if detection.Matched && !selected {
+			detection.Selected = true
+			selected = true
+		}
+		if checkVariables, ok := normalizer.(api.CheckVariables); ok {
+			for _, key := range checkVariables.GetCheckVariables() {
+				if value, ok := env[key]; ok {
+					detection.Variables[key] = value
+				}
+			}
+		}
+		if checkDescriber, ok := normalizer.(api.CheckDescriber); ok {
+			detection.Details = checkDescriber.DescribeCheck(env)
+		}
+
+		detections = append(detections, detection)
 	}
 
-	return nil, errors.New("no matching denormalizer found")
+	return detections
 }
 
-// FormatEnvironment makes the normalized environment available in the current session
-func FormatEnvironment(normalized map[string]string, format string) (string, error) {
-	if format == "export" {
-		return setNormalizedEnvironmentExport(normalized), nil
-	} else if format == "powershell" {
-		return setNormalizedEnvironmentPowershell(normalized), nil
-	} else if format == "cmd" {
-		return setNormalizedEnvironmentCmd(normalized), nil
+// Propagate passes the variables to the subsequent steps of the pipeline, using the mechanism of the detected ci service
+func Propagate(env map[string]string, vars map[string]string, opts api.PropagateOptions) (string, error) {
+	normalizer, err := DetectNormalizer(env)
+	if err != nil {
+		return "", err
 	}
 
-	return "", errors.New("unsupported format: " + format)
-}
-
-func GetDefaultFormat() string {
-	if runtime.GOOS == "linux" {
-		return "export"
-	} else if runtime.GOOS == "windows" {
-		return "powershell"
+	propagator, ok := normalizer.(api.Propagator)
+	if !ok {
+		return "", fmt.Errorf("propagation is not supported by %s", normalizer.GetName())
 	}
 
-	return ""
+	return propagator.Propagate(env, vars, opts)
 }
 
-func SetProcessEnvironment(normalized map[string]string) {
-	for key, element := range normalized {
-		err := os.Setenv(key, element)
-		if err != nil {
-			log.Err(err).Str("key", key).Str("value", element).Msg("failed to set env property")
-		}
+// WriteSummary publishes the markdown summary using the mechanism of the detected ci service, if not supported the markdown is returned to be written to stdout
+func WriteSummary(env map[string]string, markdown string) (string, error) {
+	normalizer, err := DetectNormalizer(env)
+	if err != nil {
+		return "", err
 	}
-}
-
-func setNormalizedEnvironmentExport(normalized map[string]string) string {
-	var sb strings.Builder
 
-	for key, element := range normalized {
-		// print via stdout and escape values
-		sb.WriteString(fmt.Sprintf("export %s=\"%s\"\n", key, strings.ReplaceAll(element, "\"", "\\\"")))
+	writer, ok := normalizer.(api.SummaryWriter)
+	if !ok {
+		return markdown, nil
 	}
 
-	return sb.String()
+	return writer.WriteSummary(env, markdown)
 }
 
-func setNormalizedEnvironmentPowershell(normalized map[string]string) string {
-	var sb strings.Builder
-
-	for key, element := range normalized {
-		// print via stdout and escape values
-		sb.WriteString(fmt.Sprintf("$env:%s=\"%s\";\n", key, strings.ReplaceAll(element, "\"", "\\\"")))
+// Denormalize will generate ci variables for the target service
+func Denormalize(target string, env v1.Spec) (map[string]string, error) {
+	// denormalize
+	for _, normalizer := range normalizers {
+		if target == normalizer.GetSlug() {
+			log.Debug().Msg("Matched " + normalizer.GetName() + ", not checking for any other matches.")
+			normalized, err := normalizer.Denormalize(env)
+			return normalized, err
+		} else {
+			log.Debug().Msg("Didn't match in " + normalizer.GetName())
+		}
 	}
 
-	return sb.String()
+	return nil, errors.New("no matching denormalizer found")
 }
 
-func setNormalizedEnvironmentCmd(normalized map[string]string) string {
-	var sb strings.Builder
-
+func SetProcessEnvironment(normalized map[string]string) {
 	for key, element := range normalized {
-		// print via stdout and escape values
-		sb.WriteString(fmt.Sprintf("set %s=%s\n", key, element))
+		err := os.Setenv(key, element)
+		if err != nil {
+			log.Err(err).Str("key", key).Str("value", element).Msg("failed to set env property")
+		}
 	}
-
-	return sb.String()
 }
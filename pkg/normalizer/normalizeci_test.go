@@ -0,0 +1,99 @@
+package normalizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectNormalizer(t *testing.T) {
+	n, err := DetectNormalizer(map[string]string{"GITLAB_CI": "true"})
+	assert.NoError(t, err)
+	assert.Equal(t, "gitlab-ci", n.GetSlug())
+
+	n, err = DetectNormalizer(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "local-git", n.GetSlug())
+}
+
+func TestPropagate(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "github_env")
+	_, err := Propagate(map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_ENV": envFile}, map[string]string{"NCI": "true"}, api.PropagateOptions{})
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(envFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "NCI<<ghadelimiter_")
+}
+
+func TestPropagate_NotSupported(t *testing.T) {
+	_, err := Propagate(map[string]string{}, map[string]string{"NCI": "true"}, api.PropagateOptions{})
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestWriteSummary(t *testing.T) {
+	summaryFile := filepath.Join(t.TempDir(), "step_summary")
+	output, err := WriteSummary(map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_STEP_SUMMARY": summaryFile}, "### Build context\n")
+	assert.NoError(t, err)
+	assert.Empty(t, output)
+
+	content, err := os.ReadFile(summaryFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "### Build context\n", string(content))
+}
+
+func TestWriteSummary_Stdout(t *testing.T) {
+	output, err := WriteSummary(map[string]string{}, "### Build context\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "### Build context\n", output)
+}
+
+func TestDetectAll(t *testing.T) {
+	detections := DetectAll(map[string]string{"GITLAB_CI": "true", "GITHUB_ACTIONS": "false", "CI_SERVICE_NAME": "other"})
+
+	var matched []string
+	for _, detection := range detections {
+		if detection.Matched {
+			matched = append(matched, detection.Slug)
+		}
+		switch detection.Slug {
+		case "gitlab-ci":
+			assert.True(t, detection.Selected)
+			assert.Equal(t, map[string]string{"GITLAB_CI": "true"}, detection.Variables)
+		case "github-actions":
+			assert.Equal(t, map[string]string{"GITHUB_ACTIONS": "false"}, detection.Variables)
+		case "appveyor":
+			assert.Equal(t, map[string]string{"CI_SERVICE_NAME": "other"}, detection.Variables)
+		case "local-git":
+			assert.False(t, detection.Selected)
+			assert.Empty(t, detection.Variables)
+			assert.Contains(t, detection.Details, "git repository")
+		case "generic":
+			assert.Contains(t, detection.Details, "only used if requested explicitly")
+		}
+	}
+	assert.Equal(t, []string{"gitlab-ci", "local-git"}, matched)
+}
+
+func TestNormalizeEnvWithProvenance(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	spec, prov, err := NormalizeEnvWithProvenance(map[string]string{"GITLAB_CI": "true", "CI_PROJECT_TITLE": "cienvsamples", "CI_JOB_NAME": "build"})
+	assert.NoError(t, err)
+	assert.Equal(t, "cienvsamples", spec.Project.Name)
+	assert.Equal(t, v1.Source{Kind: v1.SourceEnv, Key: "CI_PROJECT_TITLE"}, prov["project.name"])
+	assert.Equal(t, v1.Source{Kind: v1.SourceEnv, Key: "CI_JOB_NAME"}, prov["pipeline.jobSlug"])
+	assert.Equal(t, v1.SourceDefault, prov["worker.type"].Kind)
+
+	// every field with a value must have a source
+	for _, field := range spec.Fields() {
+		if field.Value != "" {
+			assert.Contains(t, prov, field.Path)
+		}
+	}
+}
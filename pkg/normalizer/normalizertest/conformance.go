@@ -0,0 +1,312 @@
+// Package normalizertest provides a round-trip conformance harness for api.Normalizer implementations.
+// It lives in its own package (like net/http/httptest), the normalizer package imports all implementations and their tests can't import it back.
+package normalizertest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cidverse/go-vcs"
+	"github.com/cidverse/go-vcs/mocks"
+	"github.com/cidverse/go-vcs/vcsapi"
+	"github.com/cidverse/normalizeci/pkg/envstruct"
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/cidverse/normalizeci/pkg/projectdetails"
+	"github.com/cidverse/normalizeci/pkg/vcsrepository"
+	"github.com/gosimple/slug"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ErrDenormalizeNotSupported is returned by RoundTrip for normalizers that can't generate their native environment
+var ErrDenormalizeNotSupported = errors.New("normalizer does not support denormalize")
+
+// FieldDifference is a field (by env name) that changed during a round trip
+type FieldDifference struct {
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (d FieldDifference) String() string {
+	return fmt.Sprintf("%s: expected %q, got %q", d.Field, d.Expected, d.Actual)
+}
+
+// RandomSpec generates a random but valid spec, as the given normalizer would produce it
+func RandomSpec(r *rand.Rand, normalizer api.Normalizer) v1.Spec {
+	spec := v1.Create(normalizer.GetName(), normalizer.GetSlug())
+
+	// repository host, the github and gitlab normalizers can only run on their own host
+	hostServer, hostType := "github.com", "github"
+	if normalizer.GetSlug() == "gitlab-ci" || (normalizer.GetSlug() != "github-actions" && r.Intn(2) == 0) {
+		hostServer, hostType = "gitlab.com", "gitlab"
+	}
+	owner := randomWord(r, 8)
+	name := randomWord(r, 10)
+	projectPath := owner + "/" + name
+	projectURL := "https://" + hostServer + "/" + projectPath
+
+	// worker
+	spec.Worker = v1.Worker{
+		Id:      randomNumber(r, 8),
+		Name:    "runner-" + randomWord(r, 6),
+		Type:    "vm",
+		OS:      randomChoice(r, []string{"ubuntu22", "ubuntu24", "win22", "macos14"}) + ":" + randomNumber(r, 8) + "." + randomNumber(r, 1),
+		Version: randomNumber(r, 1) + "." + randomNumber(r, 1) + "." + randomNumber(r, 1),
+		Arch:    randomChoice(r, []string{"linux", "windows", "darwin"}) + "/" + randomChoice(r, []string{"amd64", "arm64"}),
+	}
+
+	// pipeline
+	stageName := randomWord(r, 5)
+	jobName := randomWord(r, 5) + " " + randomWord(r, 4)
+	spec.Pipeline = v1.Pipeline{
+		Id:           randomNumber(r, 10),
+		Trigger:      randomChoice(r, randomTriggers(normalizer.GetSlug())),
+		StageId:      randomNumber(r, 10),
+		StageName:    stageName,
+		StageSlug:    slug.Make(stageName),
+		JobId:        randomNumber(r, 10),
+		JobName:      jobName,
+		JobSlug:      slug.Make(jobName),
+		JobStartedAt: time.Unix(1600000000+r.Int63n(200000000), 0).UTC().Format(time.RFC3339),
+		Attempt:      fmt.Sprintf("%d", 1+r.Intn(3)),
+		ConfigFile:   "." + randomWord(r, 6) + ".yml",
+	}
+	spec.Pipeline.Url = randomPipelineURL(normalizer.GetSlug(), spec.Pipeline, projectURL, owner, name)
+
+	// repository
+	spec.Repository = v1.Repository{
+		Kind:       "git",
+		Remote:     projectURL + ".git",
+		HostServer: hostServer,
+		HostType:   hostType,
+		Status:     "clean",
+	}
+
+	// project
+	spec.Project = v1.Project{
+		Id:            randomNumber(r, 9),
+		Name:          name,
+		Path:          projectPath,
+		Slug:          slug.Make(owner + "-" + name),
+		Description:   randomWord(r, 6) + " " + randomWord(r, 8),
+		Topics:        randomWord(r, 4) + "," + randomWord(r, 5),
+		IssueUrl:      projectURL + "/issues/{ID}",
+		Stargazers:    randomNumber(r, 2),
+		Forks:         randomNumber(r, 2),
+		Dir:           "/builds/" + projectPath,
+		Url:           projectURL,
+		DefaultBranch: "main",
+	}
+
+	// commit
+	refType, refName := "branch", randomChoice(r, []string{"main", "develop", "feature/" + randomWord(r, 6)})
+	refVCS := "refs/heads/" + refName
+	if spec.Pipeline.Trigger != common.PipelineTriggerMergeRequest && r.Intn(4) == 0 {
+		refType, refName = "tag", fmt.Sprintf("v%d.%d.%d", r.Intn(3), r.Intn(10), r.Intn(10))
+		refVCS = "refs/tags/" + refName
+	}
+	hash := randomHex(r, 40)
+	authorName := randomWord(r, 5) + " " + randomWord(r, 7)
+	authorEmail := randomWord(r, 5) + "@example.com"
+	spec.Commit = v1.Commit{
+		RefType:        refType,
+		RefName:        refName,
+		RefPath:        refType + "/" + refName,
+		RefSlug:        slug.Make(refName),
+		RefVCS:         refVCS,
+		RefRelease:     vcsrepository.ToReleaseName(refName),
+		HashShort:      hash[:8],
+		Hash:           hash,
+		AuthorName:     authorName,
+		AuthorEmail:    authorEmail,
+		CommitterName:  authorName,
+		CommitterEmail: authorEmail,
+		Title:          randomChoice(r, []string{"feat", "fix", "chore"}) + ": " + randomWord(r, 6) + " " + randomWord(r, 4),
+		Description:    randomWord(r, 8) + " " + randomWord(r, 6),
+		Count:          "0",
+	}
+
+	// merge request
+	if spec.Pipeline.Trigger == common.PipelineTriggerMergeRequest {
+		spec.MergeRequest = v1.MergeRequest{
+			Id:               randomNumber(r, 3),
+			Title:            spec.Commit.Title,
+			SourceBranchName: refName,
+			SourceHash:       hash,
+			TargetBranchName: "main",
+			TargetHash:       randomHex(r, 40),
+		}
+	}
+
+	// flags
+	spec.Flags.DeployFreeze = randomChoice(r, []string{"true", "false"})
+
+	return spec
+}
+
+// MockSpecEnvironment mocks the vcs client and project details to match the spec and blocks all api requests of the normalizers
+func MockSpecEnvironment(t *testing.T, spec v1.Spec) {
+	head := vcsapi.VCSRef{Type: spec.Commit.RefType, Value: spec.Commit.RefName, Hash: spec.Commit.Hash}
+
+	mockClient := mocks.NewClient(t)
+	mockClient.On("VCSType").Return(spec.Repository.Kind).Maybe()
+	mockClient.On("VCSRemote").Return(spec.Repository.Remote).Maybe()
+	mockClient.On("VCSHostServer", mock.Anything).Return(spec.Repository.HostServer).Maybe()
+	mockClient.On("VCSHostType", mock.Anything).Return(spec.Repository.HostType).Maybe()
+	mockClient.On("VCSHead").Return(head, nil).Maybe()
+	mockClient.On("VCSRefToInternalRef", mock.Anything).Return(spec.Commit.RefVCS).Maybe()
+	mockClient.On("FindCommitByHash", mock.Anything, false).Return(vcsapi.Commit{
+		ShortHash:   spec.Commit.HashShort,
+		Hash:        spec.Commit.Hash,
+		Message:     spec.Commit.Title,
+		Description: spec.Commit.Description,
+		Author:      vcsapi.CommitAuthor{Name: spec.Commit.AuthorName, Email: spec.Commit.AuthorEmail},
+		Committer:   vcsapi.CommitAuthor{Name: spec.Commit.CommitterName, Email: spec.Commit.CommitterEmail},
+	}, nil).Maybe()
+	vcs.MockClient = mockClient
+
+	project := spec.Project
+	projectdetails.MockProjectDetails = &project
+
+	// api requests (workflow runs) are answered with errors
+	httpmock.Activate()
+
+	t.Cleanup(func() {
+		vcs.MockClient = nil
+		projectdetails.MockProjectDetails = nil
+		httpmock.DeactivateAndReset()
+	})
+}
+
+// RoundTrip denormalizes the spec, checks that the result is detected by the normalizer, normalizes it again with a mocked environment and returns all fields that changed
+func RoundTrip(t *testing.T, normalizer api.Normalizer, spec v1.Spec) ([]FieldDifference, error) {
+	if !normalizer.SupportsDenormalize() {
+		return nil, ErrDenormalizeNotSupported
+	}
+
+	denormalized, err := normalizer.Denormalize(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to denormalize: %w", err)
+	}
+	if !normalizer.Check(denormalized) {
+		return nil, fmt.Errorf("denormalized environment is not detected by %s", normalizer.GetName())
+	}
+
+	MockSpecEnvironment(t, spec)
+	normalized, err := normalizer.Normalize(denormalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize: %w", err)
+	}
+
+	return DiffEnv(envstruct.StructToEnvMap(spec), envstruct.StructToEnvMap(normalized)), nil
+}
+
+// AssertRoundTrip denormalizes the spec, normalizes the result again and asserts that the given fields (by env name) are unchanged
+func AssertRoundTrip(t *testing.T, normalizer api.Normalizer, spec v1.Spec, fields []string) {
+	denormalized, err := normalizer.Denormalize(spec)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, normalizer.Check(denormalized), "denormalized environment should be detected by %s", normalizer.GetName())
+
+	normalized, err := normalizer.Normalize(denormalized)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	expected := envstruct.StructToEnvMap(spec)
+	actual := envstruct.StructToEnvMap(normalized)
+	for _, field := range fields {
+		assert.Equal(t, expected[field], actual[field], field)
+	}
+}
+
+// DiffEnv compares two environments and returns the differences, sorted by field name
+func DiffEnv(expected map[string]string, actual map[string]string) []FieldDifference {
+	var differences []FieldDifference
+
+	for key, value := range expected {
+		if actual[key] != value {
+			differences = append(differences, FieldDifference{Field: key, Expected: value, Actual: actual[key]})
+		}
+	}
+	for key, value := range actual {
+		if _, ok := expected[key]; !ok && value != "" {
+			differences = append(differences, FieldDifference{Field: key, Actual: value})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Field < differences[j].Field
+	})
+
+	return differences
+}
+
+// randomTriggers returns the triggers the normalizer can detect, the native environment of some ci systems doesn't reveal every trigger
+func randomTriggers(normalizerSlug string) []string {
+	switch normalizerSlug {
+	case "appveyor":
+		return []string{common.PipelineTriggerPush, common.PipelineTriggerManual, common.PipelineTriggerSchedule, common.PipelineTriggerMergeRequest}
+	case "circleci":
+		return []string{common.PipelineTriggerUnknown, common.PipelineTriggerMergeRequest}
+	case "generic":
+		return []string{common.PipelineTriggerCLI, common.PipelineTriggerMergeRequest}
+	default:
+		return []string{common.PipelineTriggerPush, common.PipelineTriggerManual, common.PipelineTriggerSchedule, common.PipelineTriggerMergeRequest, common.PipelineTriggerBuild}
+	}
+}
+
+// randomPipelineURL returns a pipeline url in the format the normalizer generates
+func randomPipelineURL(normalizerSlug string, pipeline v1.Pipeline, projectURL string, owner string, name string) string {
+	switch normalizerSlug {
+	case "azure-devops":
+		return fmt.Sprintf("https://dev.azure.com/%s/%s/_build/results?buildId=%s", owner, name, pipeline.Id)
+	case "appveyor":
+		return fmt.Sprintf("https://ci.appveyor.com/project/%s/%s/builds/%s", owner, name, pipeline.Id)
+	case "github-actions":
+		return fmt.Sprintf("%s/actions/runs/%s", projectURL, pipeline.Id)
+	default:
+		return fmt.Sprintf("%s/-/jobs/%s", projectURL, pipeline.JobId)
+	}
+}
+
+func randomWord(r *rand.Rand, length int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		sb.WriteByte(letters[r.Intn(len(letters))])
+	}
+	return sb.String()
+}
+
+func randomHex(r *rand.Rand, length int) string {
+	const digits = "0123456789abcdef"
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		sb.WriteByte(digits[r.Intn(len(digits))])
+	}
+	return sb.String()
+}
+
+func randomNumber(r *rand.Rand, length int) string {
+	var sb strings.Builder
+	sb.WriteByte(byte('1' + r.Intn(9)))
+	for i := 1; i < length; i++ {
+		sb.WriteByte(byte('0' + r.Intn(10)))
+	}
+	return sb.String()
+}
+
+func randomChoice(r *rand.Rand, values []string) string {
+	return values[r.Intn(len(values))]
+}
@@ -0,0 +1,38 @@
+package normalizertest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/cidverse/normalizeci/pkg/normalizer/githubactions"
+	"github.com/stretchr/testify/assert"
+)
+
+// undetectedNormalizer denormalizes into an environment that isn't detected by its own Check
+type undetectedNormalizer struct {
+	api.Normalizer
+}
+
+func (n undetectedNormalizer) Check(env map[string]string) bool {
+	return false
+}
+
+func TestDiffEnv(t *testing.T) {
+	differences := DiffEnv(
+		map[string]string{"NCI_A": "a", "NCI_B": "b", "NCI_C": ""},
+		map[string]string{"NCI_A": "a", "NCI_B": "x", "NCI_D": "d"},
+	)
+
+	assert.Equal(t, []FieldDifference{
+		{Field: "NCI_B", Expected: "b", Actual: "x"},
+		{Field: "NCI_D", Actual: "d"},
+	}, differences)
+}
+
+func TestRoundTrip_NotDetected(t *testing.T) {
+	n := undetectedNormalizer{Normalizer: githubactions.NewNormalizer()}
+
+	_, err := RoundTrip(t, n, RandomSpec(rand.New(rand.NewSource(1)), n))
+	assert.ErrorContains(t, err, "not detected")
+}
@@ -0,0 +1,123 @@
+package normalizer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/cidverse/normalizeci/pkg/normalizer/localgit"
+	"github.com/cidverse/normalizeci/pkg/vcsrepository"
+	"github.com/gosimple/slug"
+)
+
+// SimulateOptions are the overrides applied to the spec of the local repository
+type SimulateOptions struct {
+	Event        string            // Event is a normalized trigger or a native event name of the target (e.g. pull_request, merge_request_event)
+	Branch       string            // Branch overrides the current branch
+	Tag          string            // Tag simulates a tag pipeline, takes precedence over Branch
+	MergeRequest string            // MergeRequest is the id of the simulated merge request, defaults to 1 for merge request events
+	TargetBranch string            // TargetBranch is the target branch of the merge request, defaults to the default branch of the project
+	Inputs       map[string]string // Inputs are the custom input parameters of the pipeline
+}
+
+// SimulateTrigger returns the normalized trigger for a normalized trigger or a native event name of the target ci service
+func SimulateTrigger(target api.Normalizer, event string) (string, error) {
+	if slices.Contains(common.PipelineTriggers, event) {
+		return event, nil
+	}
+	if n, ok := target.(api.TriggerNormalizer); ok {
+		if trigger := n.NormalizeTrigger(event); trigger != common.PipelineTriggerUnknown {
+			return trigger, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown event %q for %s", event, target.GetName())
+}
+
+// Simulate normalizes the local repository, applies the overrides and returns the spec together with the native variables of the target ci service
+func Simulate(env map[string]string, target string, opts SimulateOptions) (v1.Spec, v1.Provenance, map[string]string, error) {
+	var targetNormalizer api.Normalizer
+	for _, n := range normalizers {
+		if n.GetSlug() == target {
+			targetNormalizer = n
+		}
+	}
+	if targetNormalizer == nil || !targetNormalizer.SupportsDenormalize() {
+		return v1.Spec{}, nil, nil, fmt.Errorf("simulation of %q is not supported", target)
+	}
+
+	spec, prov, err := localgit.NewNormalizer().NormalizeWithProvenance(env)
+	if err != nil {
+		return spec, prov, nil, err
+	}
+
+	spec.ServiceName = targetNormalizer.GetName()
+	spec.ServiceSlug = targetNormalizer.GetSlug()
+	prov.Set("serviceName", v1.SourceOverride, "as")
+	prov.Set("serviceSlug", v1.SourceOverride, "as")
+
+	// ref
+	if opts.Tag != "" {
+		simulateRef(&spec, prov, "tag", opts.Tag, "refs/tags/")
+	} else if opts.Branch != "" {
+		simulateRef(&spec, prov, "branch", opts.Branch, "refs/heads/")
+	}
+
+	// trigger
+	if opts.Event != "" {
+		trigger, err := SimulateTrigger(targetNormalizer, opts.Event)
+		if err != nil {
+			return spec, prov, nil, err
+		}
+		spec.Pipeline.Trigger = trigger
+		prov.Set("pipeline.trigger", v1.SourceOverride, "event")
+	}
+
+	// merge request
+	if opts.MergeRequest != "" || spec.Pipeline.Trigger == common.PipelineTriggerMergeRequest {
+		spec.Pipeline.Trigger = common.PipelineTriggerMergeRequest
+		spec.MergeRequest = v1.MergeRequest{
+			Id:               nciutil.FirstNonEmpty([]string{opts.MergeRequest, "1"}),
+			Title:            spec.Commit.Title,
+			SourceBranchName: spec.Commit.RefName,
+			SourceHash:       spec.Commit.Hash,
+			TargetBranchName: nciutil.FirstNonEmpty([]string{opts.TargetBranch, spec.Project.DefaultBranch, "main"}),
+		}
+		for _, field := range spec.Fields() {
+			if strings.HasPrefix(field.Path, "mergeRequest.") && field.Value != "" {
+				prov.Set(field.Path, v1.SourceOverride, "merge-request")
+			}
+		}
+	}
+
+	// inputs
+	if len(opts.Inputs) > 0 {
+		spec.Pipeline.Input = opts.Inputs
+		for key := range opts.Inputs {
+			prov.Set("pipeline.inputs."+key, v1.SourceOverride, "input")
+		}
+	}
+
+	denormalized, err := targetNormalizer.Denormalize(spec)
+	if err != nil {
+		return spec, prov, nil, fmt.Errorf("denormalization failed: %w", err)
+	}
+
+	return spec, prov, denormalized, nil
+}
+
+func simulateRef(spec *v1.Spec, prov v1.Provenance, refType string, refName string, vcsPrefix string) {
+	spec.Commit.RefType = refType
+	spec.Commit.RefName = refName
+	spec.Commit.RefPath = refType + "/" + refName
+	spec.Commit.RefSlug = slug.Make(refName)
+	spec.Commit.RefVCS = vcsPrefix + refName
+	spec.Commit.RefRelease = vcsrepository.ToReleaseName(refName)
+	for _, field := range []string{"commit.refType", "commit.refName", "commit.refPath", "commit.refSlug", "commit.refVcs", "commit.refRelease"} {
+		prov.Set(field, v1.SourceOverride, refType)
+	}
+}
@@ -0,0 +1,77 @@
+package normalizer
+
+import (
+	"testing"
+
+	"github.com/cidverse/normalizeci/pkg/ncispec/common"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+	"github.com/cidverse/normalizeci/pkg/normalizer/api"
+	"github.com/cidverse/normalizeci/pkg/normalizer/azuredevops"
+	"github.com/cidverse/normalizeci/pkg/normalizer/circleci"
+	"github.com/cidverse/normalizeci/pkg/normalizer/githubactions"
+	"github.com/cidverse/normalizeci/pkg/normalizer/gitlabci"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateTrigger(t *testing.T) {
+	tests := []struct {
+		target  api.Normalizer
+		event   string
+		trigger string
+	}{
+		{githubactions.NewNormalizer(), "pull_request", common.PipelineTriggerMergeRequest},
+		{githubactions.NewNormalizer(), "workflow_dispatch", common.PipelineTriggerManual},
+		{gitlabci.NewNormalizer(), "merge_request_event", common.PipelineTriggerMergeRequest},
+		{gitlabci.NewNormalizer(), "web", common.PipelineTriggerManual},
+		{gitlabci.NewNormalizer(), "api", common.PipelineTriggerManual},
+		{gitlabci.NewNormalizer(), "trigger", common.PipelineTriggerBuild},
+		{azuredevops.NewNormalizer(), "PullRequest", common.PipelineTriggerMergeRequest},
+		{gitlabci.NewNormalizer(), "push", common.PipelineTriggerPush},
+		{circleci.NewNormalizer(), "schedule", common.PipelineTriggerSchedule},
+	}
+	for _, test := range tests {
+		result, err := SimulateTrigger(test.target, test.event)
+		assert.NoError(t, err)
+		assert.Equal(t, test.trigger, result, test.event)
+	}
+
+	_, err := SimulateTrigger(githubactions.NewNormalizer(), "deployment")
+	assert.ErrorContains(t, err, "unknown event")
+	_, err = SimulateTrigger(githubactions.NewNormalizer(), "merge_request_event")
+	assert.ErrorContains(t, err, "unknown event")
+}
+
+func TestSimulate_GitHubPullRequest(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	spec, prov, env, err := Simulate(map[string]string{}, "github-actions", SimulateOptions{Event: "pull_request", Branch: "feat/simulate", MergeRequest: "42"})
+	assert.NoError(t, err)
+	assert.Equal(t, "github-actions", spec.ServiceSlug)
+	assert.Equal(t, common.PipelineTriggerMergeRequest, spec.Pipeline.Trigger)
+	assert.Equal(t, "feat/simulate", spec.MergeRequest.SourceBranchName)
+	assert.Equal(t, "main", spec.MergeRequest.TargetBranchName)
+	assert.Equal(t, v1.Source{Kind: v1.SourceOverride, Key: "branch"}, prov["commit.refName"])
+	assert.Equal(t, v1.Source{Kind: v1.SourceOverride, Key: "merge-request"}, prov["mergeRequest.id"])
+
+	assert.Equal(t, "true", env["GITHUB_ACTIONS"])
+	assert.Equal(t, "pull_request", env["GITHUB_EVENT_NAME"])
+	assert.Equal(t, "refs/pull/42/merge", env["GITHUB_REF"])
+	assert.Equal(t, "feat/simulate", env["GITHUB_HEAD_REF"])
+}
+
+func TestSimulate_GitLabTag(t *testing.T) {
+	nciutil.MockVCSClient(t)
+
+	spec, _, env, err := Simulate(map[string]string{}, "gitlab-ci", SimulateOptions{Tag: "v1.2.0", Inputs: map[string]string{"ENVIRONMENT": "staging"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.0", spec.Commit.RefRelease)
+	assert.Equal(t, "v1.2.0", env["CI_COMMIT_TAG"])
+	assert.Equal(t, "true", env["GITLAB_CI"])
+	assert.Equal(t, map[string]string{"ENVIRONMENT": "staging"}, spec.Pipeline.Input)
+}
+
+func TestSimulate_NotSupported(t *testing.T) {
+	_, _, _, err := Simulate(map[string]string{}, "local-git", SimulateOptions{})
+	assert.ErrorContains(t, err, "not supported")
+}
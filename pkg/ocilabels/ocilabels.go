@@ -0,0 +1,113 @@
+package ocilabels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/cidverse/normalizeci/pkg/nciutil"
+)
+
+// pre-defined annotation keys, see https://github.com/opencontainers/image-spec/blob/main/annotations.md
+const (
+	AnnotationCreated     = "org.opencontainers.image.created"
+	AnnotationURL         = "org.opencontainers.image.url"
+	AnnotationSource      = "org.opencontainers.image.source"
+	AnnotationVersion     = "org.opencontainers.image.version"
+	AnnotationRevision    = "org.opencontainers.image.revision"
+	AnnotationVendor      = "org.opencontainers.image.vendor"
+	AnnotationLicenses    = "org.opencontainers.image.licenses"
+	AnnotationRefName     = "org.opencontainers.image.ref.name"
+	AnnotationTitle       = "org.opencontainers.image.title"
+	AnnotationDescription = "org.opencontainers.image.description"
+)
+
+// Options holds values that are not part of the spec or overwrite the values derived from it
+type Options struct {
+	Created  string // Created is the RFC 3339 build date, defaults to the job start time
+	Licenses string // Licenses is a SPDX license expression
+	Vendor   string // Vendor is the distributing entity, defaults to the project namespace
+}
+
+// FromSpec maps the spec to the OCI annotation keys, empty values are omitted
+func FromSpec(spec v1.Spec, opts Options) map[string]string {
+	vendor := opts.Vendor
+	if vendor == "" && strings.Contains(spec.Project.Path, "/") {
+		vendor = spec.Project.Path[:strings.LastIndex(spec.Project.Path, "/")]
+	}
+
+	labels := map[string]string{
+		AnnotationCreated:     nciutil.FirstNonEmpty([]string{opts.Created, spec.Pipeline.JobStartedAt}),
+		AnnotationURL:         spec.Project.Url,
+		AnnotationSource:      nciutil.FirstNonEmpty([]string{spec.Project.Url, spec.Repository.Remote}),
+		AnnotationVersion:     spec.Commit.RefRelease,
+		AnnotationRevision:    spec.Commit.Hash,
+		AnnotationVendor:      vendor,
+		AnnotationLicenses:    opts.Licenses,
+		AnnotationRefName:     spec.Commit.RefName,
+		AnnotationTitle:       spec.Project.Name,
+		AnnotationDescription: spec.Project.Description,
+	}
+
+	for key, value := range labels {
+		if value == "" {
+			delete(labels, key)
+		}
+	}
+
+	return labels
+}
+
+// Format renders the labels for docker build (docker), docker buildx bake (bake) or as key=value list (plain).
+// kind is either label or annotation, target is the bake target the labels are added to.
+func Format(labels map[string]string, format string, kind string, target string) (string, error) {
+	if kind != "label" && kind != "annotation" {
+		return "", errors.New("unsupported kind: " + kind)
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	switch format {
+	case "docker":
+		args := make([]string, 0, len(keys))
+		for _, key := range keys {
+			args = append(args, fmt.Sprintf("--%s %s", kind, nciutil.QuotePosix(key+"="+labels[key])))
+		}
+		return strings.Join(args, " ") + "\n", nil
+	case "bake":
+		var content interface{} = labels
+		if kind == "annotation" {
+			annotations := make([]string, 0, len(keys))
+			for _, key := range keys {
+				annotations = append(annotations, key+"="+labels[key])
+			}
+			content = annotations
+		}
+
+		bake := map[string]interface{}{
+			"target": map[string]interface{}{
+				target: map[string]interface{}{kind + "s": content},
+			},
+		}
+		data, err := json.MarshalIndent(bake, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to render bake file: %w", err)
+		}
+		return string(data) + "\n", nil
+	case "plain":
+		var sb strings.Builder
+		for _, key := range keys {
+			sb.WriteString(key + "=" + nciutil.EncodeLineBreaks(labels[key]) + "\n")
+		}
+		return sb.String(), nil
+	}
+
+	return "", errors.New("unsupported format: " + format)
+}
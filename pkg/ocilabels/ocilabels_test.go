@@ -0,0 +1,83 @@
+package ocilabels
+
+import (
+	"testing"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSpec() v1.Spec {
+	spec := v1.Create("GitHub Actions", "github-actions")
+	spec.Project.Name = "normalizeci"
+	spec.Project.Path = "cidverse/normalizeci"
+	spec.Project.Url = "https://github.com/cidverse/normalizeci"
+	spec.Project.Description = "it's a test"
+	spec.Commit.Hash = "f1e2d3c4b5a6"
+	spec.Commit.RefName = "v1.2.3"
+	spec.Commit.RefRelease = "1.2.3"
+	spec.Pipeline.JobStartedAt = "2024-01-02T03:04:05Z"
+	return spec
+}
+
+func TestFromSpec(t *testing.T) {
+	labels := FromSpec(testSpec(), Options{Licenses: "MIT"})
+
+	assert.Equal(t, map[string]string{
+		AnnotationCreated:     "2024-01-02T03:04:05Z",
+		AnnotationURL:         "https://github.com/cidverse/normalizeci",
+		AnnotationSource:      "https://github.com/cidverse/normalizeci",
+		AnnotationVersion:     "1.2.3",
+		AnnotationRevision:    "f1e2d3c4b5a6",
+		AnnotationVendor:      "cidverse",
+		AnnotationLicenses:    "MIT",
+		AnnotationRefName:     "v1.2.3",
+		AnnotationTitle:       "normalizeci",
+		AnnotationDescription: "it's a test",
+	}, labels)
+}
+
+func TestFromSpecOptions(t *testing.T) {
+	spec := testSpec()
+	spec.Project.Description = ""
+	labels := FromSpec(spec, Options{Created: "2025-01-01T00:00:00Z", Vendor: "ACME"})
+
+	assert.Equal(t, "2025-01-01T00:00:00Z", labels[AnnotationCreated])
+	assert.Equal(t, "ACME", labels[AnnotationVendor])
+	assert.NotContains(t, labels, AnnotationDescription)
+	assert.NotContains(t, labels, AnnotationLicenses)
+}
+
+func TestFormatDocker(t *testing.T) {
+	content, err := Format(map[string]string{AnnotationTitle: "app", AnnotationDescription: "it's a test"}, "docker", "label", "default")
+
+	assert.NoError(t, err)
+	assert.Equal(t, `--label 'org.opencontainers.image.description=it'\''s a test' --label 'org.opencontainers.image.title=app'`+"\n", content)
+}
+
+func TestFormatBake(t *testing.T) {
+	labels := map[string]string{AnnotationTitle: "app", AnnotationRevision: "abc"}
+
+	content, err := Format(labels, "bake", "label", "image")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"target":{"image":{"labels":{"org.opencontainers.image.title":"app","org.opencontainers.image.revision":"abc"}}}}`, content)
+
+	content, err = Format(labels, "bake", "annotation", "default")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"target":{"default":{"annotations":["org.opencontainers.image.revision=abc","org.opencontainers.image.title=app"]}}}`, content)
+}
+
+func TestFormatPlain(t *testing.T) {
+	content, err := Format(map[string]string{AnnotationTitle: "app", AnnotationDescription: "line1\nline2"}, "plain", "label", "default")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "org.opencontainers.image.description=line1\\nline2\norg.opencontainers.image.title=app\n", content)
+}
+
+func TestFormatUnsupported(t *testing.T) {
+	_, err := Format(map[string]string{}, "xml", "label", "default")
+	assert.Error(t, err)
+
+	_, err = Format(map[string]string{}, "docker", "tag", "default")
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Masterminds/semver/v3"
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/gosimple/slug"
+)
+
+var markdownCellEscaper = strings.NewReplacer(
+	"|", `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+	"\r", "<br>",
+)
+
+// Funcs returns the helper functions available in templates, in addition to the text/template builtins
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"slug":             slug.Make,
+		"truncate":         truncate,
+		"default":          defaultValue,
+		"isSemver":         isSemver,
+		"semverMajor":      semverPart(func(v *semver.Version) string { return fmt.Sprint(v.Major()) }),
+		"semverMinor":      semverPart(func(v *semver.Version) string { return fmt.Sprint(v.Minor()) }),
+		"semverPatch":      semverPart(func(v *semver.Version) string { return fmt.Sprint(v.Patch()) }),
+		"semverPrerelease": semverPart(func(v *semver.Version) string { return v.Prerelease() }),
+		"semverMetadata":   semverPart(func(v *semver.Version) string { return v.Metadata() }),
+		"date":             formatDate,
+		"now":              func() string { return time.Now().UTC().Format(time.RFC3339) },
+		"env":              os.Getenv,
+		"upper":            strings.ToUpper,
+		"lower":            strings.ToLower,
+		"trim":             strings.TrimSpace,
+		"replace":          func(old string, new string, value string) string { return strings.ReplaceAll(value, old, new) },
+		"markdownCell":     markdownCell,
+	}
+}
+
+// Parse parses the template, the name is used in error messages (e.g. the file name)
+func Parse(name string, content string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(Funcs()).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// Render renders the template with the spec as root object, errors contain the template name and line number
+func Render(name string, content string, spec v1.Spec) (string, error) {
+	tmpl, err := Parse(name, content)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err = tmpl.Execute(&sb, spec); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+// truncate shortens the value to at most length characters
+func truncate(length int, value string) string {
+	if length < 0 || utf8.RuneCountInString(value) <= length {
+		return value
+	}
+
+	return string([]rune(value)[:length])
+}
+
+// markdownCell escapes the value for use in a markdown table cell, pipes are escaped and line breaks are replaced with <br>
+func markdownCell(value string) string {
+	return markdownCellEscaper.Replace(value)
+}
+
+// defaultValue returns the fallback if the value is empty
+func defaultValue(fallback string, value string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+func isSemver(value string) bool {
+	_, err := semver.NewVersion(value)
+	return err == nil
+}
+
+// semverPart creates a function that returns a part of a semantic version, the template fails if the value is not a valid version
+func semverPart(part func(v *semver.Version) string) func(value string) (string, error) {
+	return func(value string) (string, error) {
+		v, err := semver.NewVersion(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a semantic version", value)
+		}
+
+		return part(v), nil
+	}
+}
+
+// formatDate formats a RFC 3339 timestamp with a go layout, e.g. 2006-01-02
+func formatDate(layout string, value string) (string, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a RFC 3339 timestamp", value)
+	}
+
+	return t.Format(layout), nil
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"testing"
+
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSpec() v1.Spec {
+	spec := v1.Create("GitHub Actions", "github-actions")
+	spec.Project.Name = "My Project"
+	spec.Commit.RefRelease = "1.2.3-rc.1+build.5"
+	spec.Commit.Hash = "f1e2d3c4b5a6"
+	spec.Commit.Title = "a very long commit title"
+	spec.Pipeline.JobStartedAt = "2024-01-02T03:04:05Z"
+	return spec
+}
+
+func TestRender(t *testing.T) {
+	content, err := Render("version.json.tmpl", `{"version":"{{ .Commit.RefRelease }}","commit":"{{ .Commit.Hash | truncate 7 }}"}`, testSpec())
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"version":"1.2.3-rc.1+build.5","commit":"f1e2d3c"}`, content)
+}
+
+func TestRenderHelpers(t *testing.T) {
+	t.Setenv("NCI_RENDER_TEST", "from-env")
+
+	for tmpl, expected := range map[string]string{
+		`{{ .Project.Name | slug }}`:                     "my-project",
+		`{{ .Commit.Title | truncate 6 }}`:               "a very",
+		`{{ .Commit.Title | truncate 100 }}`:             "a very long commit title",
+		`{{ .Commit.Description | default "none" }}`:     "none",
+		`{{ .Commit.Hash | default "none" }}`:            "f1e2d3c4b5a6",
+		`{{ semverMajor .Commit.RefRelease }}`:           "1",
+		`{{ semverMinor .Commit.RefRelease }}`:           "2",
+		`{{ semverPatch .Commit.RefRelease }}`:           "3",
+		`{{ semverPrerelease .Commit.RefRelease }}`:      "rc.1",
+		`{{ semverMetadata .Commit.RefRelease }}`:        "build.5",
+		`{{ isSemver "feature-abc" }}`:                   "false",
+		`{{ date "2006-01-02" .Pipeline.JobStartedAt }}`: "2024-01-02",
+		`{{ env "NCI_RENDER_TEST" }}`:                    "from-env",
+		`{{ .Project.Name | upper }}`:                    "MY PROJECT",
+		`{{ .Commit.Title | replace " " "_" }}`:          "a_very_long_commit_title",
+	} {
+		content, err := Render("test", tmpl, testSpec())
+		assert.NoError(t, err, tmpl)
+		assert.Equal(t, expected, content, tmpl)
+	}
+}
+
+func TestRenderErrorLineNumbers(t *testing.T) {
+	_, err := Render("release.md.tmpl", "# Release\n\n{{ .Commit.Unknown }}\n", testSpec())
+	assert.ErrorContains(t, err, "release.md.tmpl:3")
+
+	_, err = Render("release.md.tmpl", "# Release\n{{ if }}\n", testSpec())
+	assert.ErrorContains(t, err, "release.md.tmpl:2")
+
+	_, err = Render("release.md.tmpl", "\n\n\n{{ semverMajor .Commit.Hash }}", testSpec())
+	assert.ErrorContains(t, err, "release.md.tmpl:4")
+	assert.ErrorContains(t, err, "not a semantic version")
+}
@@ -0,0 +1,32 @@
+package render
+
+import (
+	v1 "github.com/cidverse/normalizeci/pkg/ncispec/v1"
+)
+
+// SummaryTemplate is the default template of the build context summary
+const SummaryTemplate = `### Build context
+
+| | |
+|---|---|
+| Commit | ` + "`{{ .Commit.HashShort }}`" + ` {{ .Commit.Title | markdownCell }} |
+| Author | {{ .Commit.AuthorName | markdownCell }} |
+| Ref | {{ .Commit.RefType }} ` + "`{{ .Commit.RefName | markdownCell }}`" + ` |
+{{- if .MergeRequest.Id }}
+| Merge request | #{{ .MergeRequest.Id }} {{ .MergeRequest.Title | markdownCell }} (` + "`{{ .MergeRequest.SourceBranchName | markdownCell }}` → `{{ .MergeRequest.TargetBranchName | markdownCell }}`" + `) |
+{{- end }}
+| Trigger | {{ .Pipeline.Trigger }} |
+| Worker | {{ .Worker.Name | markdownCell }} ({{ .Worker.OS | default "unknown" | markdownCell }}, {{ .Worker.Arch }}) |
+{{- if or .Project.Url .Pipeline.Url }}
+| Links | {{ if .Project.Url }}[Project]({{ .Project.Url }}){{ end }}{{ if and .Project.Url .Pipeline.Url }} · {{ end }}{{ if .Pipeline.Url }}[Pipeline]({{ .Pipeline.Url }}){{ end }} |
+{{- end }}
+`
+
+// Summary renders the build context summary, the default template is used if the template is empty
+func Summary(name string, template string, spec v1.Spec) (string, error) {
+	if template == "" {
+		return Render("summary", SummaryTemplate, spec)
+	}
+
+	return Render(name, template, spec)
+}
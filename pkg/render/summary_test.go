@@ -0,0 +1,46 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	spec := testSpec()
+	spec.Commit.HashShort = "f1e2d3c4"
+	spec.Commit.Title = "fix | pipe"
+	spec.Commit.AuthorName = "Jane Doe"
+	spec.Commit.RefType = "branch"
+	spec.Commit.RefName = "main"
+	spec.Pipeline.Trigger = "merge_request"
+	spec.Pipeline.Url = "https://ci.example.com/pipelines/1"
+	spec.Worker.Name = "runner-1"
+	spec.Worker.OS = "ubuntu:22.04"
+	spec.Worker.Arch = "linux/amd64"
+	spec.MergeRequest.Id = "42"
+	spec.MergeRequest.Title = "Add feature"
+	spec.MergeRequest.SourceBranchName = "feature"
+	spec.MergeRequest.TargetBranchName = "main"
+
+	content, err := Summary("", "", spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "### Build context\n\n| | |\n|---|---|\n"+
+		"| Commit | `f1e2d3c4` fix \\| pipe |\n"+
+		"| Author | Jane Doe |\n"+
+		"| Ref | branch `main` |\n"+
+		"| Merge request | #42 Add feature (`feature` → `main`) |\n"+
+		"| Trigger | merge_request |\n"+
+		"| Worker | runner-1 (ubuntu:22.04, linux/amd64) |\n"+
+		"| Links | [Pipeline](https://ci.example.com/pipelines/1) |\n", content)
+}
+
+func TestSummaryCustomTemplate(t *testing.T) {
+	content, err := Summary("custom.md.tmpl", "Release {{ .Commit.RefRelease }}", testSpec())
+	assert.NoError(t, err)
+	assert.Equal(t, "Release 1.2.3-rc.1+build.5", content)
+}
+
+func TestMarkdownCell(t *testing.T) {
+	assert.Equal(t, "a \\| b<br>c<br>d", markdownCell("a | b\r\nc\nd"))
+}